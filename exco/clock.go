@@ -0,0 +1,251 @@
+package exco
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Clock tells the time and waits for durations to pass. It lets time based
+// callbacks such as Timeout, Retry and WaitFor be tested without real delays.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+	// Sleep waits for d to pass, returning early with ctx.Err() when ctx is done.
+	Sleep(ctx context.Context, d time.Duration) error
+	// WithTimeout returns a copy of ctx which is canceled after d passes.
+	WithTimeout(ctx context.Context, d time.Duration) (context.Context, context.CancelFunc)
+}
+
+type clockKey struct{}
+
+// WithClock returns a copy of ctx carrying the clock used by time based callbacks.
+func WithClock(ctx context.Context, clock Clock) context.Context {
+	return context.WithValue(ctx, clockKey{}, clock)
+}
+
+func clockFrom(ctx context.Context) Clock {
+	if clock, ok := ctx.Value(clockKey{}).(Clock); ok && clock != nil {
+		return clock
+	}
+
+	return realClock{}
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) Sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func (realClock) WithTimeout(ctx context.Context, d time.Duration) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(ctx, d)
+}
+
+type fakeWaiter struct {
+	at   time.Time
+	fire func()
+}
+
+// FakeClock is a Clock whose time only moves when Advance is called.
+type FakeClock struct {
+	mu      sync.Mutex
+	cond    *sync.Cond
+	now     time.Time
+	waiters []*fakeWaiter
+}
+
+// NewFakeClock returns a FakeClock set to now.
+func NewFakeClock(now time.Time) *FakeClock {
+	c := &FakeClock{now: now}
+	c.cond = sync.NewCond(&c.mu)
+
+	return c
+}
+
+// Now returns the current fake time.
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+// Sleep waits until the fake time is advanced by d or ctx is done.
+func (c *FakeClock) Sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	ch := make(chan struct{})
+	w := c.addWaiter(d, func() { close(ch) })
+
+	select {
+	case <-ctx.Done():
+		c.removeWaiter(w)
+		return ctx.Err()
+	case <-ch:
+		return nil
+	}
+}
+
+// WithTimeout returns a copy of ctx which is canceled with context.DeadlineExceeded
+// once the fake time is advanced by d.
+func (c *FakeClock) WithTimeout(ctx context.Context, d time.Duration) (context.Context, context.CancelFunc) {
+	inner, cancel := context.WithCancelCause(ctx)
+	fc := &fakeTimeoutCtx{Context: inner, deadline: c.Now().Add(d), done: make(chan struct{})}
+
+	go func() {
+		<-inner.Done()
+		fc.finish(inner.Err())
+	}()
+
+	expire := func() {
+		fc.finish(context.DeadlineExceeded)
+		cancel(context.DeadlineExceeded)
+	}
+
+	if d <= 0 {
+		expire()
+
+		return fc, func() {
+			cancel(context.Canceled)
+		}
+	}
+
+	w := c.addWaiter(d, expire)
+
+	return fc, func() {
+		c.removeWaiter(w)
+		fc.finish(context.Canceled)
+		cancel(context.Canceled)
+	}
+}
+
+// Advance moves the fake time forward by d, waking up every sleeper and
+// expiring every timeout due by then, in chronological order.
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+
+	c.now = c.now.Add(d)
+
+	sort.SliceStable(c.waiters, func(i, j int) bool {
+		return c.waiters[i].at.Before(c.waiters[j].at)
+	})
+
+	due := []*fakeWaiter{}
+	rest := []*fakeWaiter{}
+
+	for _, w := range c.waiters {
+		if w.at.After(c.now) {
+			rest = append(rest, w)
+		} else {
+			due = append(due, w)
+		}
+	}
+
+	c.waiters = rest
+	c.cond.Broadcast()
+	c.mu.Unlock()
+
+	for _, w := range due {
+		w.fire()
+	}
+}
+
+// Waiters returns the number of pending sleepers and timeouts.
+func (c *FakeClock) Waiters() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return len(c.waiters)
+}
+
+// BlockUntil blocks until at least n sleepers or timeouts are pending. It is
+// used to make sure a goroutine reached a Sleep before calling Advance.
+func (c *FakeClock) BlockUntil(n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for len(c.waiters) < n {
+		c.cond.Wait()
+	}
+}
+
+func (c *FakeClock) addWaiter(d time.Duration, fire func()) *fakeWaiter {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	w := &fakeWaiter{at: c.now.Add(d), fire: fire}
+	c.waiters = append(c.waiters, w)
+	c.cond.Broadcast()
+
+	return w
+}
+
+func (c *FakeClock) removeWaiter(w *fakeWaiter) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for i, waiter := range c.waiters {
+		if waiter == w {
+			c.waiters = append(c.waiters[:i], c.waiters[i+1:]...)
+			c.cond.Broadcast()
+			return
+		}
+	}
+}
+
+// fakeTimeoutCtx has its own Done channel, so contexts derived from it copy
+// its Err, context.DeadlineExceeded once expired, instead of attaching to the
+// inner cancel context.
+type fakeTimeoutCtx struct {
+	context.Context
+	deadline time.Time
+	done     chan struct{}
+
+	mu  sync.Mutex
+	err error
+}
+
+func (c *fakeTimeoutCtx) Deadline() (time.Time, bool) {
+	return c.deadline, true
+}
+
+func (c *fakeTimeoutCtx) Done() <-chan struct{} {
+	return c.done
+}
+
+func (c *fakeTimeoutCtx) Err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.err
+}
+
+func (c *fakeTimeoutCtx) finish(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.err == nil {
+		c.err = err
+		close(c.done)
+	}
+}
@@ -0,0 +1,192 @@
+package exco_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/Arsfiqball/talker/exco"
+)
+
+func TestFakeClock(t *testing.T) {
+	t.Run("should wake up sleepers when advanced", func(t *testing.T) {
+		start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		clock := exco.NewFakeClock(start)
+		done := make(chan error)
+
+		go func() {
+			done <- clock.Sleep(context.Background(), time.Minute)
+		}()
+
+		clock.BlockUntil(1)
+		clock.Advance(30 * time.Second)
+
+		if clock.Waiters() != 1 {
+			t.Fatal("sleeper should still be waiting")
+		}
+
+		clock.Advance(30 * time.Second)
+
+		if err := <-done; err != nil {
+			t.Fatal(err)
+		}
+
+		if !clock.Now().Equal(start.Add(time.Minute)) {
+			t.Errorf("now should be %s, got %s", start.Add(time.Minute), clock.Now())
+		}
+	})
+
+	t.Run("should expire timeout when advanced", func(t *testing.T) {
+		clock := exco.NewFakeClock(time.Now())
+
+		ctx, cancel := clock.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		if ctx.Err() != nil {
+			t.Fatal("ctx should not be done yet")
+		}
+
+		clock.Advance(time.Second)
+		<-ctx.Done()
+
+		if !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			t.Errorf("error should be context.DeadlineExceeded, got %v", ctx.Err())
+		}
+	})
+
+	t.Run("should expire derived contexts with deadline exceeded", func(t *testing.T) {
+		clock := exco.NewFakeClock(time.Now())
+
+		ctx, cancel := clock.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		child, cancelChild := context.WithCancel(ctx)
+		defer cancelChild()
+
+		clock.Advance(time.Second)
+		<-child.Done()
+
+		if !errors.Is(child.Err(), context.DeadlineExceeded) {
+			t.Errorf("child error should be context.DeadlineExceeded, got %v", child.Err())
+		}
+
+		if !errors.Is(context.Cause(child), context.DeadlineExceeded) {
+			t.Errorf("child cause should be context.DeadlineExceeded, got %v", context.Cause(child))
+		}
+	})
+
+	t.Run("should cancel with context.Canceled", func(t *testing.T) {
+		clock := exco.NewFakeClock(time.Now())
+
+		parent, cancelParent := context.WithCancel(context.Background())
+
+		ctx, cancel := clock.WithTimeout(parent, time.Second)
+		defer cancel()
+
+		cancelParent()
+		<-ctx.Done()
+
+		if !errors.Is(ctx.Err(), context.Canceled) {
+			t.Errorf("error should be context.Canceled, got %v", ctx.Err())
+		}
+	})
+}
+
+func TestRetryWithClock(t *testing.T) {
+	t.Run("should wait delay between retries", func(t *testing.T) {
+		clock := exco.NewFakeClock(time.Now())
+		ctx := exco.WithClock(context.Background(), clock)
+		calls := make(chan struct{}, 3)
+		errFail := errors.New("fail")
+
+		cb := exco.Retry(
+			func(ctx context.Context) error {
+				calls <- struct{}{}
+				return errFail
+			},
+			3,
+			time.Minute,
+		)
+
+		done := make(chan error)
+
+		go func() {
+			done <- cb(ctx)
+		}()
+
+		for i := 0; i < 2; i++ {
+			<-calls
+			clock.BlockUntil(1)
+			clock.Advance(time.Minute)
+		}
+
+		<-calls
+
+		if err := <-done; !errors.Is(err, errFail) {
+			t.Errorf("error should be errFail, got %v", err)
+		}
+	})
+}
+
+func TestTimeoutWithClock(t *testing.T) {
+	t.Run("should cancel callback after timeout", func(t *testing.T) {
+		clock := exco.NewFakeClock(time.Now())
+		ctx := exco.WithClock(context.Background(), clock)
+
+		cb := exco.Timeout(
+			func(ctx context.Context) error {
+				<-ctx.Done()
+				return ctx.Err()
+			},
+			time.Hour,
+		)
+
+		done := make(chan error)
+
+		go func() {
+			done <- cb(ctx)
+		}()
+
+		clock.BlockUntil(1)
+		clock.Advance(time.Hour)
+
+		if err := <-done; !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("error should be context.DeadlineExceeded, got %v", err)
+		}
+	})
+}
+
+func TestWaitForWithClock(t *testing.T) {
+	t.Run("should give up after timeout", func(t *testing.T) {
+		clock := exco.NewFakeClock(time.Now())
+		ctx := exco.WithClock(context.Background(), clock)
+
+		cb := exco.WaitFor(exco.Wait{
+			Dependencies: []exco.Dependency{
+				{Name: "db", Check: func(ctx context.Context) error {
+					return errors.New("db not ready")
+				}},
+			},
+			Interval: time.Second,
+			Timeout:  10 * time.Second,
+			Logger:   slog.New(slog.NewTextHandler(io.Discard, nil)),
+		})
+
+		done := make(chan error)
+
+		go func() {
+			done <- cb(ctx)
+		}()
+
+		// one waiter for the timeout, one for the polling interval
+		clock.BlockUntil(2)
+		clock.Advance(10 * time.Second)
+
+		if err := <-done; !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("error should be context.DeadlineExceeded, got %v", err)
+		}
+	})
+}
@@ -55,15 +55,22 @@ func Parallel(callbacks ...Callback) Callback {
 // Timeout runs callback with timeout.
 func Timeout(callback Callback, timeout time.Duration) Callback {
 	return func(ctx context.Context) error {
-		ctx, cancel := context.WithTimeout(ctx, timeout)
+		ctx, cancel := clockFrom(ctx).WithTimeout(ctx, timeout)
 		defer cancel()
 
 		return callback(ctx)
 	}
 }
 
-// Retry runs callback with retries.
+// Retry runs callback with retries, stopping early when ctx is done.
 func Retry(callback Callback, retries int, delay time.Duration) Callback {
+	return RetryIf(callback, retries, delay, func(error) bool { return true })
+}
+
+// RetryIf runs callback with retries like Retry, stopping early when retryable
+// reports false for an error. Use poco.IsRetryable to retry only the errors
+// classified as retryable.
+func RetryIf(callback Callback, retries int, delay time.Duration, retryable func(error) bool) Callback {
 	return func(ctx context.Context) error {
 		var err error
 
@@ -73,7 +80,13 @@ func Retry(callback Callback, retries int, delay time.Duration) Callback {
 				return nil
 			}
 
-			time.Sleep(delay)
+			if i == retries-1 || !retryable(err) {
+				break
+			}
+
+			if sleepErr := clockFrom(ctx).Sleep(ctx, delay); sleepErr != nil {
+				return errors.Join(err, sleepErr)
+			}
 		}
 
 		return err
@@ -99,3 +112,106 @@ func Atomic(commit Callback, rollback Callback) Callback {
 		return nil
 	}
 }
+
+// Condition is a function that decides which way a workflow goes.
+type Condition func(context.Context) (bool, error)
+
+// If runs then when cond is true, otherwise runs otherwise. Nil callbacks are skipped.
+func If(cond Condition, then Callback, otherwise Callback) Callback {
+	return func(ctx context.Context) error {
+		ok, err := cond(ctx)
+		if err != nil {
+			return err
+		}
+
+		if ok && then != nil {
+			return then(ctx)
+		}
+
+		if !ok && otherwise != nil {
+			return otherwise(ctx)
+		}
+
+		return nil
+	}
+}
+
+// Switch runs the callback of the case matching the value produced by value.
+// When no case matches, fallback runs instead, unless it is nil.
+func Switch[T comparable](value func(context.Context) (T, error), cases map[T]Callback, fallback Callback) Callback {
+	return func(ctx context.Context) error {
+		v, err := value(ctx)
+		if err != nil {
+			return err
+		}
+
+		if callback, ok := cases[v]; ok {
+			return callback(ctx)
+		}
+
+		if fallback != nil {
+			return fallback(ctx)
+		}
+
+		return nil
+	}
+}
+
+// Fallback runs primary and then each alternative in order until one succeeds.
+// When all of them fail, the errors are joined.
+func Fallback(primary Callback, alternatives ...Callback) Callback {
+	return func(ctx context.Context) error {
+		errs := []error{}
+
+		for _, callback := range append([]Callback{primary}, alternatives...) {
+			err := callback(ctx)
+			if err == nil {
+				return nil
+			}
+
+			errs = append(errs, err)
+
+			if ctx.Err() != nil {
+				break
+			}
+		}
+
+		return errors.Join(errs...)
+	}
+}
+
+// While runs callback repeatedly as long as cond is true, waiting interval
+// between iterations. A nil callback makes it a plain polling loop.
+func While(cond Condition, callback Callback, interval time.Duration) Callback {
+	return func(ctx context.Context) error {
+		for {
+			ok, err := cond(ctx)
+			if err != nil {
+				return err
+			}
+
+			if !ok {
+				return nil
+			}
+
+			if callback != nil {
+				if err := callback(ctx); err != nil {
+					return err
+				}
+			}
+
+			if err := clockFrom(ctx).Sleep(ctx, interval); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// Until runs callback repeatedly until cond is true, waiting interval between
+// iterations. A nil callback makes it a plain polling loop.
+func Until(cond Condition, callback Callback, interval time.Duration) Callback {
+	return While(func(ctx context.Context) (bool, error) {
+		ok, err := cond(ctx)
+		return !ok, err
+	}, callback, interval)
+}
@@ -4,8 +4,10 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/Arsfiqball/talker/exco"
+	"github.com/Arsfiqball/talker/poco"
 )
 
 func TestSequential(t *testing.T) {
@@ -143,6 +145,41 @@ func TestRetry(t *testing.T) {
 	})
 }
 
+func TestRetryIf(t *testing.T) {
+	t.Run("should stop retrying on error which is not retryable", func(t *testing.T) {
+		errUnavailable := poco.NewError("UNAVAILABLE", "unavailable").SetRetryable(true)
+		errInvalid := poco.NewError("INVALID", "invalid")
+
+		var res struct {
+			a int
+		}
+
+		cb := exco.RetryIf(
+			func(ctx context.Context) error {
+				res.a += 1
+
+				if res.a < 3 {
+					return errUnavailable.Wrap()
+				}
+
+				return errInvalid.Wrap()
+			},
+			5,
+			0,
+			poco.IsRetryable,
+		)
+
+		err := cb(context.Background())
+		if !errors.Is(err, errInvalid) {
+			t.Fatalf("error should be the invalid error, got %v", err)
+		}
+
+		if res.a != 3 {
+			t.Errorf("res.a should be 3, got %d", res.a)
+		}
+	})
+}
+
 func TestIgnoreError(t *testing.T) {
 	t.Run("should ignore error", func(t *testing.T) {
 		var res struct {
@@ -230,3 +267,219 @@ func TestAtomic(t *testing.T) {
 		}
 	})
 }
+
+func TestIf(t *testing.T) {
+	cond := func(v bool) exco.Condition {
+		return func(ctx context.Context) (bool, error) {
+			return v, nil
+		}
+	}
+
+	t.Run("should run then or otherwise", func(t *testing.T) {
+		var res struct {
+			then      int
+			otherwise int
+		}
+
+		then := func(ctx context.Context) error {
+			res.then += 1
+			return nil
+		}
+
+		otherwise := func(ctx context.Context) error {
+			res.otherwise += 1
+			return nil
+		}
+
+		if err := exco.If(cond(true), then, otherwise)(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := exco.If(cond(false), then, otherwise)(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := exco.If(cond(false), then, nil)(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+
+		if res.then != 1 {
+			t.Errorf("res.then should be 1, got %d", res.then)
+		}
+
+		if res.otherwise != 1 {
+			t.Errorf("res.otherwise should be 1, got %d", res.otherwise)
+		}
+	})
+
+	t.Run("should return condition error", func(t *testing.T) {
+		condErr := errors.New("cond error")
+
+		cb := exco.If(func(ctx context.Context) (bool, error) {
+			return false, condErr
+		}, nil, nil)
+
+		if err := cb(context.Background()); !errors.Is(err, condErr) {
+			t.Errorf("error should be condErr, got %v", err)
+		}
+	})
+}
+
+func TestSwitch(t *testing.T) {
+	t.Run("should run matching case or fallback", func(t *testing.T) {
+		var res []string
+
+		record := func(s string) exco.Callback {
+			return func(ctx context.Context) error {
+				res = append(res, s)
+				return nil
+			}
+		}
+
+		value := func(v string) func(context.Context) (string, error) {
+			return func(ctx context.Context) (string, error) {
+				return v, nil
+			}
+		}
+
+		cases := map[string]exco.Callback{
+			"postgres": record("postgres"),
+			"mysql":    record("mysql"),
+		}
+
+		for _, v := range []string{"mysql", "sqlite", "postgres"} {
+			if err := exco.Switch(value(v), cases, record("default"))(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		if len(res) != 3 || res[0] != "mysql" || res[1] != "default" || res[2] != "postgres" {
+			t.Errorf("unexpected result %v", res)
+		}
+	})
+}
+
+func TestFallback(t *testing.T) {
+	t.Run("should stop at first success", func(t *testing.T) {
+		var res struct {
+			a int
+			b int
+			c int
+		}
+
+		cb := exco.Fallback(
+			func(ctx context.Context) error {
+				res.a += 1
+				return errors.New("a error")
+			},
+			func(ctx context.Context) error {
+				res.b += 1
+				return nil
+			},
+			func(ctx context.Context) error {
+				res.c += 1
+				return nil
+			},
+		)
+
+		err := cb(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if res.a != 1 || res.b != 1 || res.c != 0 {
+			t.Errorf("unexpected result %+v", res)
+		}
+	})
+
+	t.Run("should join errors when all fail", func(t *testing.T) {
+		errA := errors.New("a error")
+		errB := errors.New("b error")
+
+		cb := exco.Fallback(
+			func(ctx context.Context) error {
+				return errA
+			},
+			func(ctx context.Context) error {
+				return errB
+			},
+		)
+
+		err := cb(context.Background())
+
+		if !errors.Is(err, errA) || !errors.Is(err, errB) {
+			t.Errorf("error should contain errA and errB, got %v", err)
+		}
+	})
+}
+
+func TestWhile(t *testing.T) {
+	t.Run("should loop while condition is true", func(t *testing.T) {
+		var res struct {
+			a int
+		}
+
+		cb := exco.While(
+			func(ctx context.Context) (bool, error) {
+				return res.a < 3, nil
+			},
+			func(ctx context.Context) error {
+				res.a += 1
+				return nil
+			},
+			0,
+		)
+
+		err := cb(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if res.a != 3 {
+			t.Errorf("res.a should be 3, got %d", res.a)
+		}
+	})
+
+	t.Run("should stop when context is done", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		cb := exco.While(
+			func(ctx context.Context) (bool, error) {
+				return true, nil
+			},
+			nil,
+			time.Hour,
+		)
+
+		if err := cb(ctx); !errors.Is(err, context.Canceled) {
+			t.Errorf("error should be context.Canceled, got %v", err)
+		}
+	})
+}
+
+func TestUntil(t *testing.T) {
+	t.Run("should poll until condition is true", func(t *testing.T) {
+		var res struct {
+			a int
+		}
+
+		cb := exco.Until(
+			func(ctx context.Context) (bool, error) {
+				res.a += 1
+				return res.a == 3, nil
+			},
+			nil,
+			0,
+		)
+
+		err := cb(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if res.a != 3 {
+			t.Errorf("res.a should be 3, got %d", res.a)
+		}
+	})
+}
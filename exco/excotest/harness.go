@@ -0,0 +1,224 @@
+// Package excotest provides helpers to test exco processes.
+package excotest
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Arsfiqball/talker/exco"
+)
+
+// DefaultTimeout is the default time a Harness waits for a state or a probe.
+const DefaultTimeout = 5 * time.Second
+
+// Harness runs a process in the background of a test.
+type Harness struct {
+	Timeout time.Duration // Timeout is the time to wait for a state or a probe, defaults to DefaultTimeout.
+
+	t      testing.TB
+	url    string
+	signal chan os.Signal
+	done   chan struct{}
+	err    error
+
+	mu     sync.Mutex
+	state  exco.State
+	states map[exco.State]chan struct{}
+	stop   sync.Once
+}
+
+// Start serves proc in the background with its monitor listening on a random
+// local port. Unless a logger is set, the process logs are discarded. The
+// process is stopped when the test ends, if not stopped before.
+func Start(t testing.TB, proc exco.Process) *Harness {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("excotest: listen monitor: %v", err)
+	}
+
+	h := &Harness{
+		Timeout: DefaultTimeout,
+		t:       t,
+		url:     "http://" + listener.Addr().String(),
+		signal:  make(chan os.Signal, 1),
+		done:    make(chan struct{}),
+		states:  map[exco.State]chan struct{}{},
+	}
+
+	for _, state := range []exco.State{exco.StateStarting, exco.StateStarted, exco.StateStopping, exco.StateStopped} {
+		h.states[state] = make(chan struct{})
+	}
+
+	onStateChange := proc.OnStateChange
+
+	proc.MonitorListener = listener
+	proc.OnStateChange = func(state exco.State) {
+		if onStateChange != nil {
+			onStateChange(state)
+		}
+
+		h.setState(state)
+	}
+
+	if proc.Logger == nil {
+		proc.Logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+	}
+
+	go func() {
+		defer close(h.done)
+		h.err = exco.Serve(proc, h.signal)
+	}()
+
+	t.Cleanup(func() {
+		h.Stop()
+	})
+
+	return h
+}
+
+func (h *Harness) setState(state exco.State) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.state = state
+
+	select {
+	case <-h.states[state]:
+	default:
+		close(h.states[state])
+	}
+}
+
+// URL returns the base URL of the monitor server, e.g. "http://127.0.0.1:40123".
+func (h *Harness) URL() string {
+	return h.url
+}
+
+// State returns the latest state of the process.
+func (h *Harness) State() exco.State {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return h.state
+}
+
+// WaitState blocks until the process has reached state, failing the test on timeout.
+func (h *Harness) WaitState(state exco.State) {
+	h.t.Helper()
+
+	h.mu.Lock()
+	ch, ok := h.states[state]
+	h.mu.Unlock()
+
+	if !ok {
+		h.t.Fatalf("excotest: unknown state %s", state)
+	}
+
+	select {
+	case <-ch:
+	case <-time.After(h.Timeout):
+		h.t.Fatalf("excotest: timeout waiting for state %s, current state is %s", state, h.State())
+	}
+}
+
+// Stop sends the stop signal and waits for Serve to return, returning the
+// errors of the Start and Stop callbacks. Calling Stop again returns the same result.
+func (h *Harness) Stop() error {
+	h.t.Helper()
+
+	h.stop.Do(func() {
+		h.signal <- os.Interrupt
+	})
+
+	select {
+	case <-h.done:
+	case <-time.After(h.Timeout):
+		h.t.Fatalf("excotest: timeout waiting for process to stop, current state is %s", h.State())
+	}
+
+	return h.err
+}
+
+// Probe sends a GET request to path on the monitor server and returns the
+// status code and body of the response.
+func (h *Harness) Probe(path string) (int, string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), h.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, h.url+path, nil)
+	if err != nil {
+		return 0, "", err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return 0, "", err
+	}
+
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, "", err
+	}
+
+	return resp.StatusCode, string(body), nil
+}
+
+func (h *Harness) assertProbe(path string, ok bool, contains string) {
+	h.t.Helper()
+
+	status, body, err := h.Probe(path)
+	if err != nil {
+		h.t.Errorf("excotest: probe %s: %v", path, err)
+		return
+	}
+
+	if ok && status != http.StatusOK {
+		h.t.Errorf("excotest: probe %s should be ok, got status %d: %s", path, status, body)
+		return
+	}
+
+	if !ok && status == http.StatusOK {
+		h.t.Errorf("excotest: probe %s should fail, got status %d", path, status)
+		return
+	}
+
+	if !strings.Contains(body, contains) {
+		h.t.Errorf("excotest: probe %s body should contain %q, got %q", path, contains, body)
+	}
+}
+
+// AssertLive asserts that the liveness probe succeeds.
+func (h *Harness) AssertLive() {
+	h.t.Helper()
+	h.assertProbe("/live", true, "")
+}
+
+// AssertNotLive asserts that the liveness probe fails with a body containing message.
+func (h *Harness) AssertNotLive(message string) {
+	h.t.Helper()
+	h.assertProbe("/live", false, message)
+}
+
+// AssertReady asserts that the readiness probe succeeds.
+func (h *Harness) AssertReady() {
+	h.t.Helper()
+	h.assertProbe("/ready", true, "")
+}
+
+// AssertNotReady asserts that the readiness probe fails with a body containing message.
+func (h *Harness) AssertNotReady(message string) {
+	h.t.Helper()
+	h.assertProbe("/ready", false, message)
+}
@@ -0,0 +1,141 @@
+package excotest_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/Arsfiqball/talker/exco"
+	"github.com/Arsfiqball/talker/exco/excotest"
+)
+
+func TestHarness(t *testing.T) {
+	t.Run("should run process lifecycle", func(t *testing.T) {
+		var ready atomic.Bool
+
+		h := excotest.Start(t, exco.Process{
+			Ready: func(ctx context.Context) error {
+				if !ready.Load() {
+					return errors.New("warming up")
+				}
+
+				return nil
+			},
+		})
+
+		h.WaitState(exco.StateStarted)
+
+		h.AssertLive()
+		h.AssertNotReady("warming up")
+
+		ready.Store(true)
+		h.AssertReady()
+
+		if err := h.Stop(); err != nil {
+			t.Fatal(err)
+		}
+
+		if h.State() != exco.StateStopped {
+			t.Errorf("state should be stopped, got %s", h.State())
+		}
+	})
+
+	t.Run("should return start and stop errors", func(t *testing.T) {
+		errStart := errors.New("start error")
+		errStop := errors.New("stop error")
+
+		h := excotest.Start(t, exco.Process{
+			Start: func(ctx context.Context) error {
+				return errStart
+			},
+			Stop: func(ctx context.Context) error {
+				return errStop
+			},
+		})
+
+		h.WaitState(exco.StateStarted)
+
+		err := h.Stop()
+
+		if !errors.Is(err, errStart) || !errors.Is(err, errStop) {
+			t.Errorf("error should contain start and stop errors, got %v", err)
+		}
+	})
+
+	t.Run("should stop process on cleanup", func(t *testing.T) {
+		var stopped atomic.Bool
+
+		t.Run("inner", func(t *testing.T) {
+			h := excotest.Start(t, exco.Process{
+				Stop: func(ctx context.Context) error {
+					stopped.Store(true)
+					return nil
+				},
+			})
+
+			h.WaitState(exco.StateStarted)
+		})
+
+		if !stopped.Load() {
+			t.Error("process should be stopped")
+		}
+	})
+
+	t.Run("should not report started when stopped while starting", func(t *testing.T) {
+		release := make(chan struct{})
+		stopping := make(chan struct{})
+
+		var (
+			mu     sync.Mutex
+			states []exco.State
+		)
+
+		h := excotest.Start(t, exco.Process{
+			Start: func(ctx context.Context) error {
+				<-release
+				return nil
+			},
+			Stop: func(ctx context.Context) error {
+				close(stopping)
+				return nil
+			},
+			OnStateChange: func(state exco.State) {
+				mu.Lock()
+				defer mu.Unlock()
+
+				states = append(states, state)
+			},
+		})
+
+		h.WaitState(exco.StateStarting)
+
+		stopped := make(chan error, 1)
+
+		go func() {
+			stopped <- h.Stop()
+		}()
+
+		<-stopping
+		close(release)
+
+		if err := <-stopped; err != nil {
+			t.Fatal(err)
+		}
+
+		if h.State() != exco.StateStopped {
+			t.Errorf("state should be stopped, got %s", h.State())
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		expected := []exco.State{exco.StateStarting, exco.StateStopping, exco.StateStopped}
+
+		if fmt.Sprint(states) != fmt.Sprint(expected) {
+			t.Errorf("states should be %v, got %v", expected, states)
+		}
+	})
+}
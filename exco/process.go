@@ -2,21 +2,52 @@ package exco
 
 import (
 	"context"
+	"errors"
 	"log/slog"
 	"net"
 	"net/http"
 	"os"
+	"sync"
 	"time"
 )
 
 // Process is a process that can be run.
 type Process struct {
-	Start       Callback     // Start is a callback that runs when the process starts.
-	Live        Callback     // Live is a callback that runs periodically to check if the process is still alive.
-	Ready       Callback     // Ready is a callback that runs periodically to check if the process is ready to serve requests.
-	Stop        Callback     // Stop is a callback that runs when the process stops.
-	Logger      *slog.Logger // Logger is the logger used by the process.
-	MonitorAddr string       // MonitorAddr is the address used by the process to serve health check requests.
+	Start           Callback          // Start is a callback that runs when the process starts.
+	Live            Callback          // Live is a callback that runs periodically to check if the process is still alive.
+	Ready           Callback          // Ready is a callback that runs periodically to check if the process is ready to serve requests.
+	Stop            Callback          // Stop is a callback that runs when the process stops.
+	Logger          *slog.Logger      // Logger is the logger used by the process.
+	MonitorAddr     string            // MonitorAddr is the address used by the process to serve health check requests.
+	Clock           Clock             // Clock is the clock passed to the callbacks and used for the shutdown timeout.
+	MonitorListener net.Listener      // MonitorListener is used instead of MonitorAddr to serve health check requests when set.
+	OnStateChange   func(state State) // OnStateChange is called, possibly from another goroutine, when the process changes state.
+}
+
+// State is a lifecycle state of a process.
+type State int
+
+const (
+	StateStarting State = iota // StateStarting means the Start callback is running.
+	StateStarted               // StateStarted means the Start callback returned.
+	StateStopping              // StateStopping means the stop signal was received and the Stop callback is running.
+	StateStopped               // StateStopped means the process is stopped and Serve is about to return.
+)
+
+// String returns the name of the state.
+func (s State) String() string {
+	switch s {
+	case StateStarting:
+		return "starting"
+	case StateStarted:
+		return "started"
+	case StateStopping:
+		return "stopping"
+	case StateStopped:
+		return "stopped"
+	}
+
+	return "unknown"
 }
 
 func emptyCallback(ctx context.Context) error {
@@ -44,6 +75,14 @@ func sanitizeProcess(proc Process) Process {
 		proc.Logger = slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{}))
 	}
 
+	if proc.Clock == nil {
+		proc.Clock = realClock{}
+	}
+
+	if proc.OnStateChange == nil {
+		proc.OnStateChange = func(State) {}
+	}
+
 	if proc.MonitorAddr == "" {
 		proc.MonitorAddr = ":0" // Random port
 	}
@@ -51,9 +90,9 @@ func sanitizeProcess(proc Process) Process {
 	return proc
 }
 
-func callbackToHealthCheckHandler(cb Callback) http.HandlerFunc {
+func callbackToHealthCheckHandler(cb Callback, clock Clock) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		err := cb(r.Context())
+		err := cb(WithClock(r.Context(), clock))
 		if err != nil {
 			w.WriteHeader(http.StatusServiceUnavailable)
 			w.Write([]byte(err.Error()))
@@ -65,30 +104,51 @@ func callbackToHealthCheckHandler(cb Callback) http.HandlerFunc {
 	}
 }
 
-// Serve runs the process.
-func Serve(proc Process, stopSignal chan os.Signal) {
+// Serve runs the process until stopSignal receives a signal, then returns the
+// errors of the Start and Stop callbacks.
+func Serve(proc Process, stopSignal chan os.Signal) error {
 	proc = sanitizeProcess(proc)
 
 	proc.Logger.Info("Start process")
+	proc.OnStateChange(StateStarting)
+
+	mainCtx, mainCancel := context.WithCancel(WithClock(context.Background(), proc.Clock))
+	stopping := make(chan struct{})
 
-	mainCtx, mainCancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	var stopErr error
+
+	// stateMu serializes the started and stopping transitions, so a process
+	// stopped while starting never reports started during shutdown.
+	var stateMu sync.Mutex
+	var stopBegan bool
 
 	// Health check server
+	wg.Add(1)
+
 	go func() {
+		defer wg.Done()
+
 		mux := http.NewServeMux()
 
-		mux.HandleFunc("/live", callbackToHealthCheckHandler(proc.Live))
-		mux.HandleFunc("/ready", callbackToHealthCheckHandler(proc.Ready))
+		mux.HandleFunc("/live", callbackToHealthCheckHandler(proc.Live, proc.Clock))
+		mux.HandleFunc("/ready", callbackToHealthCheckHandler(proc.Ready, proc.Clock))
 
 		server := http.Server{
 			Addr:    proc.MonitorAddr,
 			Handler: mux,
 		}
 
-		listener, err := net.Listen("tcp", server.Addr)
-		if err != nil {
-			proc.Logger.Error(err.Error())
-			return
+		listener := proc.MonitorListener
+
+		if listener == nil {
+			var err error
+
+			listener, err = net.Listen("tcp", server.Addr)
+			if err != nil {
+				proc.Logger.Error(err.Error())
+				return
+			}
 		}
 
 		defer listener.Close() // Ensure listener is closed after Serve() returns
@@ -96,9 +156,9 @@ func Serve(proc Process, stopSignal chan os.Signal) {
 		proc.Logger.Info("Monitor address: " + listener.Addr().String())
 
 		go func() {
-			<-stopSignal
+			<-stopping
 
-			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			ctx, cancel := proc.Clock.WithTimeout(context.Background(), 30*time.Second)
 			defer cancel()
 
 			err := server.Shutdown(ctx)
@@ -107,8 +167,8 @@ func Serve(proc Process, stopSignal chan os.Signal) {
 			}
 		}()
 
-		err = server.Serve(listener)
-		if err != nil {
+		err := server.Serve(listener)
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
 			proc.Logger.Error(err.Error())
 		}
 	}()
@@ -116,14 +176,20 @@ func Serve(proc Process, stopSignal chan os.Signal) {
 	// Stop process when stopSignal is received
 	go func() {
 		<-stopSignal
+		close(stopping)
 
 		proc.Logger.Info("Stop process")
 
-		stopCtx, stopCancel := context.WithCancel(context.Background())
+		stateMu.Lock()
+		stopBegan = true
+		proc.OnStateChange(StateStopping)
+		stateMu.Unlock()
 
-		err := proc.Stop(stopCtx)
-		if err != nil {
-			proc.Logger.Error(err.Error())
+		stopCtx, stopCancel := context.WithCancel(WithClock(context.Background(), proc.Clock))
+
+		stopErr = proc.Stop(stopCtx)
+		if stopErr != nil {
+			proc.Logger.Error(stopErr.Error())
 		}
 
 		stopCancel()
@@ -131,11 +197,22 @@ func Serve(proc Process, stopSignal chan os.Signal) {
 	}()
 
 	// Start process
-	err := proc.Start(mainCtx)
-	if err != nil {
-		proc.Logger.Error(err.Error())
+	startErr := proc.Start(mainCtx)
+	if startErr != nil {
+		proc.Logger.Error(startErr.Error())
 	}
 
-	// Block until mainCtx is canceled
+	stateMu.Lock()
+	if !stopBegan {
+		proc.OnStateChange(StateStarted)
+	}
+	stateMu.Unlock()
+
+	// Block until mainCtx is canceled and the monitor is shut down
 	<-mainCtx.Done()
+	wg.Wait()
+
+	proc.OnStateChange(StateStopped)
+
+	return errors.Join(startErr, stopErr)
 }
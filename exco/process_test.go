@@ -2,100 +2,103 @@ package exco_test
 
 import (
 	"context"
-	"errors"
-	"fmt"
-	"net/http"
-	"os"
+	"sync/atomic"
 	"testing"
-	"time"
 
 	"github.com/Arsfiqball/talker/exco"
+	"github.com/Arsfiqball/talker/exco/excotest"
+	"github.com/Arsfiqball/talker/poco"
 )
 
-type fakeSignal struct{}
-
-func (fakeSignal) String() string {
-	return "fake signal"
-}
-
-func (fakeSignal) Signal() {}
-
-func httpGet(url string) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
-	defer cancel()
-
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
-	if err != nil {
-		return err
-	}
-
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return err
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		return errors.New("status code is not 200")
-	}
-
-	return nil
-}
-
 func TestProcess(t *testing.T) {
 	t.Run("should start and stop process", func(t *testing.T) {
 		var res struct {
-			started bool
-			stopped bool
+			started atomic.Bool
+			stopped atomic.Bool
 		}
 
+		var states []exco.State
+
 		proc := exco.Process{
-			MonitorAddr: ":8086",
 			Start: func(ctx context.Context) error {
-				res.started = true
+				res.started.Store(true)
 				return nil
 			},
 			Stop: func(ctx context.Context) error {
-				res.stopped = true
+				res.stopped.Store(true)
 				return nil
 			},
+			OnStateChange: func(state exco.State) {
+				states = append(states, state)
+			},
 		}
 
-		sig := make(chan os.Signal, 1)
+		h := excotest.Start(t, proc)
+		h.WaitState(exco.StateStarted)
 
-		go func() {
-			time.Sleep(100 * time.Millisecond)
+		if !res.started.Load() {
+			t.Error("process should be started")
+		}
 
-			if !res.started {
-				t.Error("process should be started")
-			}
+		if res.stopped.Load() {
+			t.Error("process should not be stopped yet")
+		}
 
-			if res.stopped {
-				t.Error("process should not be stopped yet")
-			}
+		h.AssertLive()
+		h.AssertReady()
 
-			time.Sleep(100 * time.Millisecond)
+		if err := h.Stop(); err != nil {
+			t.Fatal(err)
+		}
 
-			err := httpGet("http://localhost:8086/live")
-			if err != nil {
-				t.Error(fmt.Errorf("live check not ok: %w", err))
-			}
+		if !res.stopped.Load() {
+			t.Error("process should be stopped")
+		}
+
+		expected := []exco.State{exco.StateStarting, exco.StateStarted, exco.StateStopping, exco.StateStopped}
+
+		if len(states) != len(expected) {
+			t.Fatalf("states should be %v, got %v", expected, states)
+		}
 
-			err = httpGet("http://localhost:8086/ready")
-			if err != nil {
-				t.Error(fmt.Errorf("ready check not ok: %w", err))
+		for i := range expected {
+			if states[i] != expected[i] {
+				t.Errorf("states should be %v, got %v", expected, states)
 			}
+		}
+	})
+}
 
-			time.Sleep(100 * time.Millisecond)
+type eventCounter struct {
+	events atomic.Int32
+}
 
-			sig <- fakeSignal{} // stop process
-		}()
+func (c *eventCounter) OnEvent(ctx context.Context, name string, attrs []any) {
+	c.events.Add(1)
+}
+
+func TestProcessAsyncListener(t *testing.T) {
+	t.Run("should flush async listener on stop", func(t *testing.T) {
+		counter := &eventCounter{}
+		async := poco.NewAsyncListener(counter)
+		obs := poco.NewObserver(poco.WithListener(async))
+
+		h := excotest.Start(t, exco.Process{
+			Start: func(ctx context.Context) error {
+				obs.Event(ctx, "started", nil)
+				return nil
+			},
+			Stop: async.Shutdown,
+		})
 
-		exco.Serve(proc, sig)
+		h.WaitState(exco.StateStarted)
 
-		time.Sleep(100 * time.Millisecond)
+		if err := h.Stop(); err != nil {
+			t.Fatal(err)
+		}
 
-		if !res.stopped {
-			t.Error("process should be stopped")
+		if counter.events.Load() != 1 {
+			t.Errorf("event should be flushed on stop, got %d", counter.events.Load())
 		}
 	})
 }
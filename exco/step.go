@@ -0,0 +1,88 @@
+package exco
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Arsfiqball/talker/poco"
+)
+
+type stepPathKey struct{}
+
+// StepError is an error returned by a failed step, annotated with the step path.
+type StepError struct {
+	Path string // Path is the slash separated path of the failed step, e.g. "startup/db/migrate".
+	Err  error  // Err is the error returned by the step callback.
+}
+
+// Error returns the error message prefixed by the step path.
+func (e *StepError) Error() string {
+	return e.Path + ": " + e.Err.Error()
+}
+
+// Unwrap returns the error returned by the step callback.
+func (e *StepError) Unwrap() error {
+	return e.Err
+}
+
+// StepPath returns the path of the step currently running in ctx.
+func StepPath(ctx context.Context) string {
+	path, _ := ctx.Value(stepPathKey{}).(string)
+	return path
+}
+
+// Step runs callback as a named step. Nested steps form a path such as
+// "startup/db/migrate". When ctx carries an observer (see poco.WithObserver), a span
+// named after the path is opened for the step, carrying its duration and outcome
+// as attributes and the error it returned. A failing step returns a
+// *StepError, unless the error already comes from a nested step.
+func Step(name string, callback Callback) Callback {
+	return func(ctx context.Context) error {
+		path := name
+
+		if parent := StepPath(ctx); parent != "" {
+			path = parent + "/" + name
+		}
+
+		ctx = context.WithValue(ctx, stepPathKey{}, path)
+
+		obs := poco.ObserverFrom(ctx)
+
+		if obs == nil {
+			return wrapStepError(path, callback(ctx))
+		}
+
+		ctx, span := obs.StartSpan(ctx, path, []any{"step", path})
+		defer span.End()
+
+		clock := clockFrom(ctx)
+		start := clock.Now()
+		err := callback(ctx)
+		duration := clock.Now().Sub(start)
+
+		if err != nil {
+			span.SetAttributes([]any{"duration", duration, "outcome", "error"})
+			span.RecordError(err)
+			span.SetStatus(poco.SpanStatusError, err.Error())
+		} else {
+			span.SetAttributes([]any{"duration", duration, "outcome", "ok"})
+			span.SetStatus(poco.SpanStatusOK, "")
+		}
+
+		return wrapStepError(path, err)
+	}
+}
+
+func wrapStepError(path string, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var stepErr *StepError
+
+	if errors.As(err, &stepErr) {
+		return err
+	}
+
+	return &StepError{Path: path, Err: err}
+}
@@ -0,0 +1,181 @@
+package exco_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Arsfiqball/talker/exco"
+	"github.com/Arsfiqball/talker/poco"
+	"github.com/Arsfiqball/talker/poco/pocotest"
+)
+
+type stepRecorder struct {
+	mu    sync.Mutex
+	spans []string
+	attrs map[string][]any
+	state map[string]poco.SpanStatus
+}
+
+type stepSpanRecorder struct {
+	parent *stepRecorder
+	name   string
+}
+
+func (r *stepRecorder) OnSpanStart(ctx context.Context, name string, attrs []any) (context.Context, poco.SpanRecorder) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.spans = append(r.spans, name)
+
+	return ctx, &stepSpanRecorder{parent: r, name: name}
+}
+
+func (r *stepSpanRecorder) SetAttributes(attrs []any) {
+	r.parent.mu.Lock()
+	defer r.parent.mu.Unlock()
+
+	r.parent.attrs[r.name] = append(r.parent.attrs[r.name], attrs...)
+}
+
+func (r *stepSpanRecorder) AddEvent(name string, attrs []any) {}
+
+func (r *stepSpanRecorder) RecordError(err error) {}
+
+func (r *stepSpanRecorder) SetStatus(status poco.SpanStatus, description string) {
+	r.parent.mu.Lock()
+	defer r.parent.mu.Unlock()
+
+	r.parent.state[r.name] = status
+}
+
+func (r *stepSpanRecorder) End() {}
+
+func TestStep(t *testing.T) {
+	t.Run("should wrap error with step path", func(t *testing.T) {
+		errMigrate := errors.New("migration failed")
+
+		cb := exco.Step("startup", exco.Sequential(
+			exco.Step("cache", func(ctx context.Context) error {
+				return nil
+			}),
+			exco.Step("db", exco.Step("migrate", func(ctx context.Context) error {
+				return errMigrate
+			})),
+		))
+
+		err := cb(context.Background())
+
+		var stepErr *exco.StepError
+
+		if !errors.As(err, &stepErr) {
+			t.Fatalf("error should be a StepError, got %v", err)
+		}
+
+		if stepErr.Path != "startup/db/migrate" {
+			t.Errorf("path should be startup/db/migrate, got %s", stepErr.Path)
+		}
+
+		if !errors.Is(err, errMigrate) {
+			t.Error("error should wrap errMigrate")
+		}
+
+		if err.Error() != "startup/db/migrate: migration failed" {
+			t.Errorf("unexpected error message: %s", err.Error())
+		}
+	})
+
+	t.Run("should expose step path in context", func(t *testing.T) {
+		var path string
+
+		cb := exco.Step("a", exco.Step("b", func(ctx context.Context) error {
+			path = exco.StepPath(ctx)
+			return nil
+		}))
+
+		err := cb(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if path != "a/b" {
+			t.Errorf("path should be a/b, got %s", path)
+		}
+	})
+
+	t.Run("should trace steps with observer", func(t *testing.T) {
+		rec := &stepRecorder{attrs: map[string][]any{}, state: map[string]poco.SpanStatus{}}
+		obs := poco.NewObserver(poco.WithListener(rec))
+		ctx := poco.WithObserver(context.Background(), obs)
+
+		cb := exco.Step("startup", exco.Parallel(
+			exco.Step("ok", func(ctx context.Context) error {
+				return nil
+			}),
+			exco.Step("fail", func(ctx context.Context) error {
+				return errors.New("fail")
+			}),
+		))
+
+		err := cb(ctx)
+		if err == nil {
+			t.Fatal("expected error")
+		}
+
+		if len(rec.spans) != 3 {
+			t.Fatalf("expected 3 spans, got %d", len(rec.spans))
+		}
+
+		if rec.spans[0] != "startup" {
+			t.Errorf("first span should be startup, got %s", rec.spans[0])
+		}
+
+		outcomes := map[string]string{}
+
+		for name, attrs := range rec.attrs {
+			if _, ok := attrs[1].(time.Duration); !ok {
+				t.Errorf("duration should be time.Duration, got %T", attrs[1])
+			}
+
+			outcomes[name] = attrs[3].(string)
+		}
+
+		if rec.state["startup/fail"] != poco.SpanStatusError {
+			t.Errorf("startup/fail status should be error, got %s", rec.state["startup/fail"])
+		}
+
+		if outcomes["startup/ok"] != "ok" {
+			t.Errorf("startup/ok outcome should be ok, got %s", outcomes["startup/ok"])
+		}
+
+		if outcomes["startup/fail"] != "error" {
+			t.Errorf("startup/fail outcome should be error, got %s", outcomes["startup/fail"])
+		}
+
+		if outcomes["startup"] != "error" {
+			t.Errorf("startup outcome should be error, got %s", outcomes["startup"])
+		}
+	})
+	t.Run("should nest step spans", func(t *testing.T) {
+		rec := pocotest.NewRecorder()
+		errFail := errors.New("fail")
+
+		cb := exco.Step("startup", exco.Sequential(
+			exco.Step("db", exco.Step("migrate", func(ctx context.Context) error {
+				return errFail
+			})),
+		))
+
+		_ = cb(rec.Context(context.Background()))
+
+		rec.AssertSpan(t, "startup").
+			HasAttr("outcome", "error").
+			HasChild("startup/db").
+			HasChild("startup/db/migrate").
+			HasStatus(poco.SpanStatusError).
+			HasError(errFail).
+			IsEnded()
+	})
+}
@@ -0,0 +1,147 @@
+package exco
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Dependency is an external resource the program waits for.
+type Dependency struct {
+	Name  string   // Name identifies the dependency in logs, errors and the skip list.
+	Check Callback // Check returns nil when the dependency is reachable.
+}
+
+// Wait describes how to wait for dependencies.
+type Wait struct {
+	Dependencies []Dependency  // Dependencies are the dependencies to wait for.
+	Interval     time.Duration // Interval is the delay between polling rounds, defaults to 1 second.
+	Timeout      time.Duration // Timeout is the maximum waiting time, zero means until ctx is done.
+	Logger       *slog.Logger  // Logger is the logger used to report progress.
+	SkipEnv      string        // SkipEnv is the env variable listing comma separated dependency names to skip ("*" skips all), defaults to WAIT_FOR_SKIP.
+}
+
+// WaitError is returned when some dependencies are still failing when waiting stops.
+type WaitError struct {
+	Failing []string // Failing is the names of the dependencies still failing.
+	Err     error    // Err joins the cause of the stop and the last error of each failing dependency.
+}
+
+// Error returns the names of the failing dependencies.
+func (e *WaitError) Error() string {
+	return "dependencies not ready: " + strings.Join(e.Failing, ", ")
+}
+
+// Unwrap returns the joined errors.
+func (e *WaitError) Unwrap() error {
+	return e.Err
+}
+
+func sanitizeWait(w Wait) Wait {
+	if w.Interval <= 0 {
+		w.Interval = time.Second
+	}
+
+	if w.Logger == nil {
+		w.Logger = slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{}))
+	}
+
+	if w.SkipEnv == "" {
+		w.SkipEnv = "WAIT_FOR_SKIP"
+	}
+
+	return w
+}
+
+func skipList(env string) map[string]bool {
+	skip := map[string]bool{}
+
+	for _, name := range strings.Split(os.Getenv(env), ",") {
+		name = strings.TrimSpace(name)
+
+		if name != "" {
+			skip[name] = true
+		}
+	}
+
+	return skip
+}
+
+// WaitFor polls the dependencies until all of them pass or the timeout expires.
+// Dependencies which already passed are not checked again.
+func WaitFor(w Wait) Callback {
+	w = sanitizeWait(w)
+
+	return func(ctx context.Context) error {
+		if w.Timeout > 0 {
+			var cancel context.CancelFunc
+
+			ctx, cancel = clockFrom(ctx).WithTimeout(ctx, w.Timeout)
+			defer cancel()
+		}
+
+		skip := skipList(w.SkipEnv)
+		pending := []Dependency{}
+
+		for _, dep := range w.Dependencies {
+			if skip["*"] || skip[dep.Name] {
+				w.Logger.Info("Skip dependency", "dependency", dep.Name)
+				continue
+			}
+
+			pending = append(pending, dep)
+		}
+
+		for attempt := 1; ; attempt++ {
+			errs := make([]error, len(pending))
+
+			var wg sync.WaitGroup
+
+			for i, dep := range pending {
+				wg.Add(1)
+
+				go func(i int, dep Dependency) {
+					defer wg.Done()
+					errs[i] = dep.Check(ctx)
+				}(i, dep)
+			}
+
+			wg.Wait()
+
+			failing := []Dependency{}
+			failingErrs := []error{}
+
+			for i, dep := range pending {
+				if errs[i] == nil {
+					w.Logger.Info("Dependency ready", "dependency", dep.Name, "attempt", attempt)
+					continue
+				}
+
+				failing = append(failing, dep)
+				failingErrs = append(failingErrs, errs[i])
+			}
+
+			if len(failing) == 0 {
+				return nil
+			}
+
+			names := make([]string, len(failing))
+
+			for i, dep := range failing {
+				names[i] = dep.Name
+			}
+
+			w.Logger.Info("Waiting for dependencies", "pending", names, "attempt", attempt, "error", errors.Join(failingErrs...).Error())
+
+			if err := clockFrom(ctx).Sleep(ctx, w.Interval); err != nil {
+				return &WaitError{Failing: names, Err: errors.Join(append([]error{err}, failingErrs...)...)}
+			}
+
+			pending = failing
+		}
+	}
+}
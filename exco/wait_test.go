@@ -0,0 +1,137 @@
+package exco_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Arsfiqball/talker/exco"
+)
+
+func TestWaitFor(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	t.Run("should wait until all dependencies pass", func(t *testing.T) {
+		var db, broker atomic.Int32
+
+		cb := exco.WaitFor(exco.Wait{
+			Dependencies: []exco.Dependency{
+				{Name: "db", Check: func(ctx context.Context) error {
+					if db.Add(1) < 3 {
+						return errors.New("db not ready")
+					}
+
+					return nil
+				}},
+				{Name: "broker", Check: func(ctx context.Context) error {
+					broker.Add(1)
+					return nil
+				}},
+			},
+			Interval: time.Second,
+			Logger:   logger,
+		})
+
+		clock := exco.NewFakeClock(time.Now())
+		done := make(chan error)
+
+		go func() {
+			done <- cb(exco.WithClock(context.Background(), clock))
+		}()
+
+		for i := 0; i < 2; i++ {
+			clock.BlockUntil(1)
+			clock.Advance(time.Second)
+		}
+
+		if err := <-done; err != nil {
+			t.Fatal(err)
+		}
+
+		if db.Load() != 3 {
+			t.Errorf("db should be checked 3 times, got %d", db.Load())
+		}
+
+		if broker.Load() != 1 {
+			t.Errorf("broker should be checked once, got %d", broker.Load())
+		}
+	})
+
+	t.Run("should report failing dependencies on timeout", func(t *testing.T) {
+		errDB := errors.New("db not ready")
+
+		cb := exco.WaitFor(exco.Wait{
+			Dependencies: []exco.Dependency{
+				{Name: "db", Check: func(ctx context.Context) error {
+					return errDB
+				}},
+				{Name: "broker", Check: func(ctx context.Context) error {
+					return nil
+				}},
+			},
+			Interval: time.Minute,
+			Timeout:  10 * time.Second,
+			Logger:   logger,
+		})
+
+		clock := exco.NewFakeClock(time.Now())
+		done := make(chan error)
+
+		go func() {
+			done <- cb(exco.WithClock(context.Background(), clock))
+		}()
+
+		// one waiter for the timeout, one for the polling interval
+		clock.BlockUntil(2)
+		clock.Advance(10 * time.Second)
+
+		err := <-done
+
+		var waitErr *exco.WaitError
+
+		if !errors.As(err, &waitErr) {
+			t.Fatalf("error should be a WaitError, got %v", err)
+		}
+
+		if len(waitErr.Failing) != 1 || waitErr.Failing[0] != "db" {
+			t.Errorf("failing should be [db], got %v", waitErr.Failing)
+		}
+
+		if !errors.Is(err, errDB) {
+			t.Error("error should wrap errDB")
+		}
+
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Error("error should wrap context.DeadlineExceeded")
+		}
+	})
+
+	t.Run("should skip dependencies listed in env", func(t *testing.T) {
+		t.Setenv("TEST_WAIT_FOR_SKIP", "db, cache")
+
+		cb := exco.WaitFor(exco.Wait{
+			Dependencies: []exco.Dependency{
+				{Name: "db", Check: func(ctx context.Context) error {
+					return errors.New("db not ready")
+				}},
+				{Name: "broker", Check: func(ctx context.Context) error {
+					return nil
+				}},
+			},
+			Timeout: 10 * time.Second,
+			Logger:  logger,
+			SkipEnv: "TEST_WAIT_FOR_SKIP",
+		})
+
+		clock := exco.NewFakeClock(time.Now())
+
+		err := cb(exco.WithClock(context.Background(), clock))
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+}
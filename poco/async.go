@@ -0,0 +1,359 @@
+package poco
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// AsyncListener moves the work of a listener off the hot path. Events, errors
+// and span updates are buffered in a bounded queue and handed to the wrapped
+// listener in batches by a background goroutine. Items arriving while the
+// queue is full are dropped and counted, except span ends which are never
+// dropped and keep the time End was called, see SpanEndAtRecorder. Span starts
+// stay synchronous because they return the span ctx, and OnError returns the
+// error unchanged since the wrapped listener runs later.
+type AsyncListener struct {
+	listener     interface{}
+	spanListener SpanHandleListener
+
+	queueSize    int
+	batchSize    int
+	interval     time.Duration
+	dropReporter func(dropped uint64)
+
+	mu       sync.Mutex
+	queue    []func()
+	closed   bool
+	dropped  uint64
+	reported uint64
+
+	flushMu sync.Mutex
+	notify  chan struct{}
+	stop    chan struct{}
+	done    chan struct{}
+}
+
+type AsyncOption func(*AsyncListener)
+
+// WithAsyncQueueSize sets the maximum number of buffered items, 1024 by default.
+// Values not greater than zero keep the default.
+func WithAsyncQueueSize(size int) AsyncOption {
+	return func(l *AsyncListener) {
+		if size > 0 {
+			l.queueSize = size
+		}
+	}
+}
+
+// WithAsyncBatchSize sets the number of buffered items which triggers a flush, 128 by default.
+// Values not greater than zero keep the default.
+func WithAsyncBatchSize(size int) AsyncOption {
+	return func(l *AsyncListener) {
+		if size > 0 {
+			l.batchSize = size
+		}
+	}
+}
+
+// WithAsyncFlushInterval sets the maximum delay before buffered items are flushed, 1 second by default.
+// Values not greater than zero keep the default.
+func WithAsyncFlushInterval(interval time.Duration) AsyncOption {
+	return func(l *AsyncListener) {
+		if interval > 0 {
+			l.interval = interval
+		}
+	}
+}
+
+// WithAsyncDropReporter sets the function called after a flush with the total
+// number of dropped items, whenever it grew. By default a warning is logged
+// with slog.Default().
+func WithAsyncDropReporter(reporter func(dropped uint64)) AsyncOption {
+	return func(l *AsyncListener) {
+		l.dropReporter = reporter
+	}
+}
+
+func NewAsyncListener(listener interface{}, opts ...AsyncOption) *AsyncListener {
+	l := &AsyncListener{
+		listener:  listener,
+		queueSize: 1024,
+		batchSize: 128,
+		interval:  time.Second,
+		dropReporter: func(dropped uint64) {
+			slog.Default().Warn("poco: async listener queue is full, items dropped", "dropped", dropped)
+		},
+		notify: make(chan struct{}, 1),
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+
+	for _, opt := range opts {
+		opt(l)
+	}
+
+	if handleListener, ok := listener.(SpanHandleListener); ok {
+		l.spanListener = handleListener
+	} else if startListener, ok := listener.(SpanListener); ok {
+		l.spanListener = legacySpanListener{listener: startListener}
+	}
+
+	go l.run()
+
+	return l
+}
+
+func (l *AsyncListener) run() {
+	defer close(l.done)
+
+	ticker := time.NewTicker(l.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-l.stop:
+			l.flush()
+			return
+		case <-l.notify:
+		case <-ticker.C:
+		}
+
+		l.flush()
+	}
+}
+
+func (l *AsyncListener) enqueue(task func()) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.closed || len(l.queue) >= l.queueSize {
+		l.dropped++
+		return
+	}
+
+	l.queue = append(l.queue, task)
+	l.notifyBatch()
+}
+
+// enqueueEnd queues task even when the queue is full. Once the listener is
+// shut down, the queue is flushed right away, so the task is never dropped.
+func (l *AsyncListener) enqueueEnd(task func()) {
+	l.mu.Lock()
+
+	l.queue = append(l.queue, task)
+	closed := l.closed
+
+	if !closed {
+		l.notifyBatch()
+	}
+
+	l.mu.Unlock()
+
+	if closed {
+		l.flush()
+	}
+}
+
+// notifyBatch wakes up the background goroutine when a batch is full. l.mu
+// must be held.
+func (l *AsyncListener) notifyBatch() {
+	if len(l.queue) >= l.batchSize {
+		select {
+		case l.notify <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (l *AsyncListener) flush() {
+	l.flushMu.Lock()
+	defer l.flushMu.Unlock()
+
+	for {
+		l.mu.Lock()
+
+		n := len(l.queue)
+
+		if n > l.batchSize {
+			n = l.batchSize
+		}
+
+		batch := l.queue[:n]
+		l.queue = l.queue[n:]
+
+		l.mu.Unlock()
+
+		if len(batch) == 0 {
+			break
+		}
+
+		for _, task := range batch {
+			task()
+		}
+	}
+
+	l.mu.Lock()
+	dropped := l.dropped
+	report := dropped > l.reported
+	l.reported = dropped
+	l.mu.Unlock()
+
+	if report && l.dropReporter != nil {
+		l.dropReporter(dropped)
+	}
+}
+
+// Dropped returns the number of items dropped because the queue was full or
+// the listener was shut down.
+func (l *AsyncListener) Dropped() uint64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.dropped
+}
+
+// Flush hands all buffered items to the wrapped listener.
+func (l *AsyncListener) Flush(ctx context.Context) error {
+	done := make(chan struct{})
+
+	go func() {
+		l.flush()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Shutdown stops accepting items, flushes the buffered ones and stops the
+// background goroutine. It can be used as, or as part of, the Stop callback of
+// an exco.Process.
+func (l *AsyncListener) Shutdown(ctx context.Context) error {
+	l.mu.Lock()
+
+	if !l.closed {
+		l.closed = true
+		close(l.stop)
+	}
+
+	l.mu.Unlock()
+
+	select {
+	case <-l.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (l *AsyncListener) OnSpanStart(ctx context.Context, name string, attrs []any) (context.Context, SpanRecorder) {
+	if l.spanListener == nil {
+		return ctx, asyncSpanRecorder{}
+	}
+
+	ctx, recorder := l.spanListener.OnSpanStart(ctx, name, attrs)
+
+	return ctx, asyncSpanRecorder{listener: l, recorder: recorder}
+}
+
+func (l *AsyncListener) OnEvent(ctx context.Context, name string, attrs []any) {
+	eventListener, ok := l.listener.(EventListener)
+
+	if !ok {
+		return
+	}
+
+	attrs = append([]any(nil), attrs...)
+
+	l.enqueue(func() {
+		eventListener.OnEvent(ctx, name, attrs)
+	})
+}
+
+func (l *AsyncListener) OnError(ctx context.Context, err error) error {
+	errorListener, ok := l.listener.(ErrorListener)
+
+	if !ok {
+		return err
+	}
+
+	l.enqueue(func() {
+		_ = errorListener.OnError(ctx, err)
+	})
+
+	return err
+}
+
+type asyncSpanRecorder struct {
+	listener *AsyncListener
+	recorder SpanRecorder
+}
+
+func (r asyncSpanRecorder) SetAttributes(attrs []any) {
+	if r.recorder == nil {
+		return
+	}
+
+	attrs = append([]any(nil), attrs...)
+
+	r.listener.enqueue(func() {
+		r.recorder.SetAttributes(attrs)
+	})
+}
+
+func (r asyncSpanRecorder) AddEvent(name string, attrs []any) {
+	if r.recorder == nil {
+		return
+	}
+
+	attrs = append([]any(nil), attrs...)
+
+	r.listener.enqueue(func() {
+		r.recorder.AddEvent(name, attrs)
+	})
+}
+
+func (r asyncSpanRecorder) RecordError(err error) {
+	if r.recorder == nil {
+		return
+	}
+
+	r.listener.enqueue(func() {
+		r.recorder.RecordError(err)
+	})
+}
+
+func (r asyncSpanRecorder) SetStatus(status SpanStatus, description string) {
+	if r.recorder == nil {
+		return
+	}
+
+	r.listener.enqueue(func() {
+		r.recorder.SetStatus(status, description)
+	})
+}
+
+func (r asyncSpanRecorder) End() {
+	if r.recorder == nil {
+		return
+	}
+
+	endAt, ok := r.recorder.(SpanEndAtRecorder)
+
+	if !ok {
+		r.listener.enqueueEnd(r.recorder.End)
+		return
+	}
+
+	now := time.Now()
+
+	r.listener.enqueueEnd(func() {
+		endAt.EndAt(now)
+	})
+}
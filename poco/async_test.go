@@ -0,0 +1,254 @@
+package poco_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Arsfiqball/talker/poco"
+	"github.com/Arsfiqball/talker/poco/pocotest"
+)
+
+type syncListener struct {
+	mu     sync.Mutex
+	events []string
+	block  chan struct{}
+}
+
+func (l *syncListener) OnEvent(ctx context.Context, name string, attrs []any) {
+	if l.block != nil {
+		<-l.block
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.events = append(l.events, name)
+}
+
+func (l *syncListener) count() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return len(l.events)
+}
+
+func TestAsyncListener(t *testing.T) {
+	t.Run("flush buffered events on shutdown", func(t *testing.T) {
+		inner := &syncListener{}
+		async := poco.NewAsyncListener(inner, poco.WithAsyncFlushInterval(time.Hour))
+		obs := poco.NewObserver(poco.WithListener(async))
+
+		for i := 0; i < 10; i++ {
+			obs.Event(context.Background(), "event", nil)
+		}
+
+		if inner.count() != 0 {
+			t.Fatal("events should be buffered")
+		}
+
+		if err := async.Shutdown(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+
+		if inner.count() != 10 {
+			t.Errorf("10 events should be flushed, got %d", inner.count())
+		}
+
+		obs.Event(context.Background(), "late", nil)
+
+		if async.Dropped() != 1 {
+			t.Errorf("events after shutdown should be dropped, got %d", async.Dropped())
+		}
+	})
+
+	t.Run("flush when batch is full", func(t *testing.T) {
+		inner := &syncListener{}
+		async := poco.NewAsyncListener(inner, poco.WithAsyncBatchSize(5), poco.WithAsyncFlushInterval(time.Hour))
+		defer async.Shutdown(context.Background())
+
+		for i := 0; i < 5; i++ {
+			async.OnEvent(context.Background(), "event", nil)
+		}
+
+		deadline := time.Now().Add(time.Second)
+
+		for inner.count() < 5 && time.Now().Before(deadline) {
+			time.Sleep(time.Millisecond)
+		}
+
+		if inner.count() != 5 {
+			t.Errorf("5 events should be flushed, got %d", inner.count())
+		}
+	})
+
+	t.Run("drop and report when queue is full", func(t *testing.T) {
+		var reported atomic.Uint64
+
+		inner := &syncListener{}
+		async := poco.NewAsyncListener(inner,
+			poco.WithAsyncQueueSize(3),
+			poco.WithAsyncFlushInterval(time.Hour),
+			poco.WithAsyncDropReporter(func(dropped uint64) {
+				reported.Store(dropped)
+			}),
+		)
+
+		for i := 0; i < 5; i++ {
+			async.OnEvent(context.Background(), "event", nil)
+		}
+
+		if err := async.Flush(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+
+		if inner.count() != 3 || async.Dropped() != 2 || reported.Load() != 2 {
+			t.Errorf("unexpected result: delivered %d, dropped %d, reported %d", inner.count(), async.Dropped(), reported.Load())
+		}
+
+		_ = async.Shutdown(context.Background())
+	})
+
+	t.Run("span updates are delivered in order", func(t *testing.T) {
+		handle := &orderListener{}
+		async := poco.NewAsyncListener(handle, poco.WithAsyncFlushInterval(time.Hour))
+		obs := poco.NewObserver(poco.WithListener(async))
+
+		_, span := obs.StartSpan(context.Background(), "span", nil)
+		span.SetAttributes([]any{"a", 1})
+		span.SetStatus(poco.SpanStatusOK, "")
+		span.End()
+
+		if len(handle.calls) != 1 {
+			t.Fatalf("span start should be synchronous, got %v", handle.calls)
+		}
+
+		_ = async.Shutdown(context.Background())
+
+		expected := []string{"start", "attributes", "status", "end"}
+
+		if len(handle.calls) != len(expected) {
+			t.Fatalf("calls should be %v, got %v", expected, handle.calls)
+		}
+
+		for i := range expected {
+			if handle.calls[i] != expected[i] {
+				t.Errorf("calls should be %v, got %v", expected, handle.calls)
+			}
+		}
+	})
+	t.Run("ignore options not greater than zero", func(t *testing.T) {
+		inner := &syncListener{}
+		async := poco.NewAsyncListener(inner,
+			poco.WithAsyncQueueSize(0),
+			poco.WithAsyncBatchSize(0),
+			poco.WithAsyncFlushInterval(0),
+		)
+
+		poco.NewObserver(poco.WithListener(async)).Event(context.Background(), "event", nil)
+
+		if err := async.Shutdown(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+
+		if inner.count() != 1 {
+			t.Errorf("event should be delivered, got %d", inner.count())
+		}
+	})
+
+	t.Run("span end is never dropped and keeps its time", func(t *testing.T) {
+		rec := pocotest.NewRecorder()
+		async := poco.NewAsyncListener(rec, poco.WithAsyncQueueSize(1), poco.WithAsyncFlushInterval(time.Hour))
+		obs := poco.NewObserver(poco.WithListener(async))
+
+		_, span := obs.StartSpan(context.Background(), "span", nil)
+		span.SetAttributes([]any{"a", 1})
+		span.End()
+
+		time.Sleep(50 * time.Millisecond)
+
+		_ = async.Shutdown(context.Background())
+
+		ended := rec.Span("span")
+
+		if !ended.Ended() {
+			t.Fatal("span should be ended")
+		}
+
+		if ended.Duration() >= 50*time.Millisecond {
+			t.Errorf("duration should not include the queueing delay, got %s", ended.Duration())
+		}
+
+		_, late := obs.StartSpan(context.Background(), "late", nil)
+		late.End()
+
+		if !rec.Span("late").Ended() {
+			t.Error("span ended after shutdown should be ended")
+		}
+	})
+
+	t.Run("slog span duration excludes the queueing delay", func(t *testing.T) {
+		var logs bytes.Buffer
+
+		slogListener := poco.NewSlogListener(slog.New(slog.NewJSONHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug})))
+		async := poco.NewAsyncListener(slogListener, poco.WithAsyncFlushInterval(time.Hour))
+		obs := poco.NewObserver(poco.WithListener(async))
+
+		_, end := obs.Span(context.Background(), "span", nil)
+		end()
+
+		time.Sleep(50 * time.Millisecond)
+
+		_ = async.Shutdown(context.Background())
+
+		var line struct {
+			Msg      string        `json:"msg"`
+			Duration time.Duration `json:"duration"`
+		}
+
+		lines := strings.Split(strings.TrimSpace(logs.String()), "\n")
+
+		if err := json.Unmarshal([]byte(lines[len(lines)-1]), &line); err != nil {
+			t.Fatal(err)
+		}
+
+		if line.Msg != "span end" || line.Duration >= 50*time.Millisecond {
+			t.Errorf("span end should be logged without the queueing delay, got %s", lines[len(lines)-1])
+		}
+	})
+}
+
+type orderListener struct {
+	calls []string
+}
+
+func (l *orderListener) OnSpanStart(ctx context.Context, name string, attrs []any) (context.Context, poco.SpanRecorder) {
+	l.calls = append(l.calls, "start")
+	return ctx, l
+}
+
+func (l *orderListener) SetAttributes(attrs []any) {
+	l.calls = append(l.calls, "attributes")
+}
+
+func (l *orderListener) AddEvent(name string, attrs []any) {
+	l.calls = append(l.calls, "event")
+}
+
+func (l *orderListener) RecordError(err error) {
+	l.calls = append(l.calls, "error")
+}
+
+func (l *orderListener) SetStatus(status poco.SpanStatus, description string) {
+	l.calls = append(l.calls, "status")
+}
+
+func (l *orderListener) End() {
+	l.calls = append(l.calls, "end")
+}
@@ -0,0 +1,127 @@
+package poco
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+const badKey = "!BADKEY"
+
+var ErrMalformedAttrs = NewError("POCO_MALFORMED_ATTRS", "malformed attributes")
+
+// Attr is a typed key/value pair. It is the same type as slog.Attr so
+// attributes can be handed to log/slog as they are.
+type Attr = slog.Attr
+
+func String(key, value string) Attr {
+	return slog.String(key, value)
+}
+
+func Int(key string, value int) Attr {
+	return slog.Int(key, value)
+}
+
+func Int64(key string, value int64) Attr {
+	return slog.Int64(key, value)
+}
+
+func Float64(key string, value float64) Attr {
+	return slog.Float64(key, value)
+}
+
+func Bool(key string, value bool) Attr {
+	return slog.Bool(key, value)
+}
+
+func Duration(key string, value time.Duration) Attr {
+	return slog.Duration(key, value)
+}
+
+func Time(key string, value time.Time) Attr {
+	return slog.Time(key, value)
+}
+
+func Any(key string, value any) Attr {
+	return slog.Any(key, value)
+}
+
+func Group(key string, attrs ...Attr) Attr {
+	return slog.Attr{Key: key, Value: slog.GroupValue(attrs...)}
+}
+
+// Attrs turns typed attributes into the attrs argument of Observer.Span,
+// Observer.StartSpan, Observer.Event and SpanHandle.SetAttributes.
+func Attrs(attrs ...Attr) []any {
+	result := make([]any, len(attrs))
+
+	for i, attr := range attrs {
+		result[i] = attr
+	}
+
+	return result
+}
+
+// validAttrs reports whether ParseAttrs would accept attrs, without allocating.
+func validAttrs(attrs []any) bool {
+	for i := 0; i < len(attrs); i++ {
+		if _, ok := attrs[i].(Attr); ok {
+			continue
+		}
+
+		if _, ok := attrs[i].(string); !ok || i == len(attrs)-1 {
+			return false
+		}
+
+		i++
+	}
+
+	return true
+}
+
+// ParseAttrs converts attrs, made of Attr values and alternating key/value
+// pairs, into typed attributes. Like log/slog, a key which is not a string or
+// has no value is kept under the "!BADKEY" key, and each of them is reported in
+// the returned error, which matches ErrMalformedAttrs.
+func ParseAttrs(attrs []any) ([]Attr, error) {
+	result := make([]Attr, 0, len(attrs))
+	errs := []error{}
+
+	for i := 0; i < len(attrs); i++ {
+		if attr, ok := attrs[i].(Attr); ok {
+			result = append(result, attr)
+			continue
+		}
+
+		key, ok := attrs[i].(string)
+
+		if !ok {
+			errs = append(errs, fmt.Errorf("attrs[%d]: key %v is not a string", i, attrs[i]))
+			result = append(result, slog.Any(badKey, attrs[i]))
+			continue
+		}
+
+		if i == len(attrs)-1 {
+			errs = append(errs, fmt.Errorf("attrs[%d]: key %q has no value", i, key))
+			result = append(result, slog.String(badKey, key))
+			continue
+		}
+
+		result = append(result, slog.Any(key, attrs[i+1]))
+		i++
+	}
+
+	if len(errs) > 0 {
+		msgs := make([]string, len(errs))
+
+		for i, err := range errs {
+			msgs[i] = err.Error()
+		}
+
+		return result, ErrMalformedAttrs.Wrap(errors.Join(errs...)).SetInfo("malformed attributes: " + strings.Join(msgs, "; "))
+	}
+
+	return result, nil
+}
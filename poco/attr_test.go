@@ -0,0 +1,126 @@
+package poco_test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Arsfiqball/talker/poco"
+	"github.com/Arsfiqball/talker/poco/pocotest"
+)
+
+const badKey = "!BADKEY"
+
+func TestParseAttrs(t *testing.T) {
+	t.Run("typed and legacy attributes", func(t *testing.T) {
+		attrs, err := poco.ParseAttrs([]any{
+			poco.String("name", "alice"),
+			"count", 3,
+			poco.Group("req", poco.Duration("took", time.Second), poco.Bool("cached", true)),
+		})
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(attrs) != 3 {
+			t.Fatalf("expected 3 attributes, got %v", attrs)
+		}
+
+		if attrs[0].Key != "name" || attrs[0].Value.String() != "alice" {
+			t.Errorf("unexpected first attribute %v", attrs[0])
+		}
+
+		if attrs[1].Key != "count" || attrs[1].Value.Kind() != slog.KindInt64 || attrs[1].Value.Int64() != 3 {
+			t.Errorf("unexpected second attribute %v", attrs[1])
+		}
+
+		if attrs[2].Value.Kind() != slog.KindGroup || len(attrs[2].Value.Group()) != 2 {
+			t.Errorf("unexpected group attribute %v", attrs[2])
+		}
+	})
+
+	t.Run("malformed pairs", func(t *testing.T) {
+		attrs, err := poco.ParseAttrs([]any{"ok", 1, 42, "dangling"})
+
+		if !errors.Is(err, poco.ErrMalformedAttrs) {
+			t.Fatalf("error should be ErrMalformedAttrs, got %v", err)
+		}
+
+		if err.Error() != `malformed attributes: attrs[2]: key 42 is not a string; attrs[3]: key "dangling" has no value` {
+			t.Errorf("unexpected error message %q", err.Error())
+		}
+
+		if len(attrs) != 3 || attrs[1].Key != "!BADKEY" || attrs[2].Key != "!BADKEY" {
+			t.Errorf("malformed pairs should be kept under !BADKEY, got %v", attrs)
+		}
+	})
+
+	t.Run("attrs helper", func(t *testing.T) {
+		args := poco.Attrs(poco.Int("a", 1), poco.Any("b", []string{"x"}))
+
+		attrs, err := poco.ParseAttrs(args)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(attrs) != 2 || attrs[0].Key != "a" || attrs[1].Key != "b" {
+			t.Errorf("unexpected attributes %v", attrs)
+		}
+	})
+}
+
+func TestObserverAttrValidation(t *testing.T) {
+	t.Run("should report malformed attributes", func(t *testing.T) {
+		var errs []error
+
+		rec := pocotest.NewRecorder()
+		obs := rec.Observer(poco.WithAttrReporter(func(ctx context.Context, err error) {
+			errs = append(errs, err)
+		}))
+		ctx := context.Background()
+
+		ctx, span := obs.StartSpan(ctx, "fetch", []any{"user.id"})
+		span.SetAttributes([]any{42, "k", "v"})
+		span.AddEvent("retry", []any{"attempt", 1})
+		obs.Event(ctx, "cache miss", []any{"key"})
+		span.End()
+
+		if len(errs) != 3 {
+			t.Fatalf("3 errors should be reported, got %v", errs)
+		}
+
+		for _, err := range errs {
+			if !errors.Is(err, poco.ErrMalformedAttrs) {
+				t.Errorf("error should match ErrMalformedAttrs, got %v", err)
+			}
+		}
+
+		if msg := errs[0].Error(); msg != `span fetch: malformed attributes: attrs[0]: key "user.id" has no value` {
+			t.Errorf("unexpected message %q", msg)
+		}
+
+		if msg := errs[2].Error(); !strings.HasPrefix(msg, "event cache miss: ") {
+			t.Errorf("unexpected message %q", msg)
+		}
+
+		rec.AssertNoErrors(t)
+		rec.AssertSpan(t, "fetch").HasAttr(badKey, 42).HasAttr("k", "v")
+	})
+
+	t.Run("should not validate by default", func(t *testing.T) {
+		rec := pocotest.NewRecorder()
+		obs := rec.Observer()
+
+		_, span := obs.StartSpan(context.Background(), "fetch", []any{"user.id"})
+		span.End()
+
+		obs.Event(context.Background(), "cache miss", []any{"key"})
+
+		rec.AssertNoErrors(t)
+		rec.AssertSpan(t, "fetch").HasStatus(poco.SpanStatusUnset)
+	})
+}
@@ -0,0 +1,253 @@
+// Package catalog reads error catalogs and generates Go sentinel declarations
+// and error references from them. It backs the pococatalog command.
+package catalog
+
+import (
+	"errors"
+	"fmt"
+	"go/token"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Catalog lists the errors of a package.
+type Catalog struct {
+	Package string  `yaml:"package" json:"package"`
+	Errors  []Entry `yaml:"errors" json:"errors"`
+}
+
+// Entry describes one error of a catalog.
+type Entry struct {
+	Code        string            `yaml:"code" json:"code"`
+	Name        string            `yaml:"name" json:"name"`
+	Message     string            `yaml:"message" json:"message"`
+	Description string            `yaml:"description" json:"description,omitempty"`
+	HTTP        int               `yaml:"http" json:"http"`
+	GRPC        string            `yaml:"grpc" json:"grpc"`
+	Retryable   bool              `yaml:"retryable" json:"retryable"`
+	Temporary   bool              `yaml:"temporary" json:"temporary"`
+	UserVisible bool              `yaml:"user_visible" json:"user_visible"`
+	Severity    string            `yaml:"severity" json:"severity,omitempty"`
+	Data        map[string]string `yaml:"data" json:"data,omitempty"`
+}
+
+// Field is a field of the data schema of an entry.
+type Field struct {
+	Name string
+	Type string
+}
+
+var grpcCodes = []string{
+	"OK", "CANCELED", "UNKNOWN", "INVALID_ARGUMENT", "DEADLINE_EXCEEDED",
+	"NOT_FOUND", "ALREADY_EXISTS", "PERMISSION_DENIED", "RESOURCE_EXHAUSTED",
+	"FAILED_PRECONDITION", "ABORTED", "OUT_OF_RANGE", "UNIMPLEMENTED",
+	"INTERNAL", "UNAVAILABLE", "DATA_LOSS", "UNAUTHENTICATED",
+}
+
+var httpToGRPC = map[int]string{
+	http.StatusBadRequest:          "INVALID_ARGUMENT",
+	http.StatusUnauthorized:        "UNAUTHENTICATED",
+	http.StatusForbidden:           "PERMISSION_DENIED",
+	http.StatusNotFound:            "NOT_FOUND",
+	http.StatusConflict:            "ALREADY_EXISTS",
+	http.StatusPreconditionFailed:  "FAILED_PRECONDITION",
+	http.StatusTooManyRequests:     "RESOURCE_EXHAUSTED",
+	499:                            "CANCELED",
+	http.StatusNotImplemented:      "UNIMPLEMENTED",
+	http.StatusServiceUnavailable:  "UNAVAILABLE",
+	http.StatusGatewayTimeout:      "DEADLINE_EXCEEDED",
+	http.StatusInternalServerError: "INTERNAL",
+}
+
+var severities = map[string]string{
+	"info":     "poco.SeverityInfo",
+	"warning":  "poco.SeverityWarning",
+	"error":    "poco.SeverityError",
+	"critical": "poco.SeverityCritical",
+}
+
+var dataTypes = map[string]bool{
+	"string": true, "bool": true, "int": true, "int64": true, "float64": true,
+	"[]string": true, "[]int": true, "any": true, "time.Time": true, "time.Duration": true,
+}
+
+// Load reads a YAML or JSON catalog file, fills in the defaults and validates it.
+func Load(path string) (Catalog, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return Catalog{}, err
+	}
+
+	return Parse(b)
+}
+
+// Parse decodes a YAML or JSON catalog, fills in the defaults and validates it.
+func Parse(b []byte) (Catalog, error) {
+	var c Catalog
+
+	if err := yaml.Unmarshal(b, &c); err != nil {
+		return Catalog{}, fmt.Errorf("catalog: %w", err)
+	}
+
+	c.fillDefaults()
+
+	if err := c.Validate(); err != nil {
+		return Catalog{}, err
+	}
+
+	return c, nil
+}
+
+func (c *Catalog) fillDefaults() {
+	for i := range c.Errors {
+		e := &c.Errors[i]
+
+		if e.Name == "" {
+			e.Name = "Err" + camelCase(e.Code)
+		}
+
+		if e.HTTP == 0 {
+			e.HTTP = http.StatusInternalServerError
+		}
+
+		if e.GRPC == "" {
+			e.GRPC = httpToGRPC[e.HTTP]
+
+			if e.GRPC == "" {
+				e.GRPC = "UNKNOWN"
+			}
+		}
+	}
+}
+
+// Validate reports every problem of the catalog.
+func (c Catalog) Validate() error {
+	errs := []error{}
+
+	if !token.IsIdentifier(c.Package) {
+		errs = append(errs, fmt.Errorf("catalog: package %q is not a valid identifier", c.Package))
+	}
+
+	codes := map[string]bool{}
+	names := map[string]bool{}
+
+	for i, e := range c.Errors {
+		switch {
+		case e.Code == "":
+			errs = append(errs, fmt.Errorf("catalog: errors[%d]: code is missing", i))
+		case codes[e.Code]:
+			errs = append(errs, fmt.Errorf("catalog: errors[%d]: code %s is duplicated", i, e.Code))
+		}
+
+		codes[e.Code] = true
+
+		switch {
+		case !token.IsIdentifier(e.Name) || !token.IsExported(e.Name):
+			errs = append(errs, fmt.Errorf("catalog: errors[%d]: name %q is not an exported identifier", i, e.Name))
+		case names[e.Name]:
+			errs = append(errs, fmt.Errorf("catalog: errors[%d]: name %s is duplicated", i, e.Name))
+		}
+
+		names[e.Name] = true
+
+		if e.Message == "" {
+			errs = append(errs, fmt.Errorf("catalog: errors[%d]: message is missing", i))
+		}
+
+		if http.StatusText(e.HTTP) == "" && e.HTTP != 499 {
+			errs = append(errs, fmt.Errorf("catalog: errors[%d]: http status %d is unknown", i, e.HTTP))
+		}
+
+		if grpcConstant(e.GRPC) == "" {
+			errs = append(errs, fmt.Errorf("catalog: errors[%d]: grpc code %s is unknown", i, e.GRPC))
+		}
+
+		if e.Severity != "" && severities[e.Severity] == "" {
+			errs = append(errs, fmt.Errorf("catalog: errors[%d]: severity %s is unknown", i, e.Severity))
+		}
+
+		fields := map[string]string{}
+
+		for _, f := range e.Fields() {
+			if !dataTypes[f.Type] {
+				errs = append(errs, fmt.Errorf("catalog: errors[%d]: data field %s has unsupported type %s", i, f.Name, f.Type))
+			}
+
+			field := camelCase(f.Name)
+
+			switch {
+			case !token.IsIdentifier(field) || !token.IsExported(field):
+				errs = append(errs, fmt.Errorf("catalog: errors[%d]: data field %q is not a valid Go field name", i, f.Name))
+			case fields[field] != "":
+				errs = append(errs, fmt.Errorf("catalog: errors[%d]: data field %s clashes with %s as Go field %s", i, f.Name, fields[field], field))
+			}
+
+			fields[field] = f.Name
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Fields returns the data schema of the entry sorted by name.
+func (e Entry) Fields() []Field {
+	fields := make([]Field, 0, len(e.Data))
+
+	for name, typ := range e.Data {
+		fields = append(fields, Field{Name: name, Type: typ})
+	}
+
+	sort.Slice(fields, func(i, j int) bool {
+		return fields[i].Name < fields[j].Name
+	})
+
+	return fields
+}
+
+// DataType returns the name of the generated data struct, or "" without schema.
+func (e Entry) DataType() string {
+	if len(e.Data) == 0 {
+		return ""
+	}
+
+	return strings.TrimPrefix(e.Name, "Err") + "Data"
+}
+
+func grpcConstant(code string) string {
+	if code == "OK" {
+		return "poco.GRPCOK"
+	}
+
+	for _, c := range grpcCodes {
+		if c == code {
+			return "poco.GRPC" + camelCase(code)
+		}
+	}
+
+	return ""
+}
+
+var initialisms = map[string]bool{
+	"ID": true, "URL": true, "URI": true, "HTTP": true, "API": true, "IP": true, "JSON": true, "SQL": true,
+}
+
+func camelCase(s string) string {
+	var b strings.Builder
+
+	for _, part := range strings.FieldsFunc(s, func(r rune) bool {
+		return r == '_' || r == '-' || r == '.' || r == ' '
+	}) {
+		if initialisms[strings.ToUpper(part)] {
+			b.WriteString(strings.ToUpper(part))
+			continue
+		}
+
+		b.WriteString(strings.ToUpper(part[:1]) + strings.ToLower(part[1:]))
+	}
+
+	return b.String()
+}
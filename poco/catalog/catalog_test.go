@@ -0,0 +1,210 @@
+package catalog_test
+
+import (
+	"encoding/json"
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+
+	"github.com/Arsfiqball/talker/poco/catalog"
+)
+
+const source = `
+package: users
+errors:
+  - code: USER_NOT_FOUND
+    message: user not found
+    description: The requested user does not exist.
+    http: 404
+    data:
+      user_id: string
+  - code: USER_LOCKED
+    name: ErrLocked
+    message: user is locked
+    http: 423
+    grpc: FAILED_PRECONDITION
+    user_visible: true
+    data:
+      until: time.Time
+  - code: UPSTREAM_UNAVAILABLE
+    message: upstream unavailable
+    http: 503
+    retryable: true
+    temporary: true
+    severity: warning
+`
+
+func TestParse(t *testing.T) {
+	t.Run("should fill defaults", func(t *testing.T) {
+		c, err := catalog.Parse([]byte(source))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if c.Package != "users" || len(c.Errors) != 3 {
+			t.Fatalf("unexpected catalog %+v", c)
+		}
+
+		e := c.Errors[0]
+
+		if e.Name != "ErrUserNotFound" {
+			t.Errorf("name should be derived from code, got %s", e.Name)
+		}
+
+		if e.GRPC != "NOT_FOUND" {
+			t.Errorf("grpc code should be derived from http status, got %s", e.GRPC)
+		}
+
+		if e.DataType() != "UserNotFoundData" {
+			t.Errorf("unexpected data type %s", e.DataType())
+		}
+
+		if c.Errors[1].GRPC != "FAILED_PRECONDITION" {
+			t.Errorf("explicit grpc code should be kept, got %s", c.Errors[1].GRPC)
+		}
+	})
+
+	t.Run("should parse JSON", func(t *testing.T) {
+		c, err := catalog.Parse([]byte(`{"package": "orders", "errors": [{"code": "ORDER_EMPTY", "message": "order is empty"}]}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if c.Errors[0].HTTP != 500 || c.Errors[0].GRPC != "INTERNAL" {
+			t.Errorf("unexpected defaults %+v", c.Errors[0])
+		}
+	})
+
+	t.Run("should report every problem", func(t *testing.T) {
+		_, err := catalog.Parse([]byte(`
+package: 1users
+errors:
+  - code: A
+    message: a
+    http: 999
+  - code: A
+    grpc: NOPE
+    severity: fatal
+    data:
+      x: chan int
+      1st: string
+      user-id: string
+      user_id: string
+`))
+		if err == nil {
+			t.Fatal("should fail")
+		}
+
+		for _, expected := range []string{
+			"package", "http status 999", "code A is duplicated", "name ErrA is duplicated",
+			"message is missing", "grpc code NOPE", "severity fatal", "unsupported type chan int",
+			`data field "1st" is not a valid Go field name`, "data field user_id clashes with user-id",
+		} {
+			if !strings.Contains(err.Error(), expected) {
+				t.Errorf("error should mention %q, got %s", expected, err)
+			}
+		}
+	})
+}
+
+func TestGenerate(t *testing.T) {
+	c, err := catalog.Parse([]byte(source))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("should generate valid Go", func(t *testing.T) {
+		b, err := catalog.GenerateGo(c)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := parser.ParseFile(token.NewFileSet(), "errors_gen.go", b, 0); err != nil {
+			t.Fatal(err)
+		}
+
+		src := string(b)
+
+		for _, expected := range []string{
+			"// Code generated by pococatalog. DO NOT EDIT.",
+			"package users",
+			`"time"`,
+			`ErrUserNotFound = poco.DefineError[UserNotFoundData]("USER_NOT_FOUND", "user not found")`,
+			"type UserNotFoundData struct",
+			"UserID string `json:\"user_id\"`",
+			"type LockedData struct",
+			`poco.Status{HTTP: 423, GRPC: poco.GRPCFailedPrecondition, Message: "user is locked"}`,
+			"poco.Status{HTTP: 404, GRPC: poco.GRPCNotFound})",
+			`poco.NewError("UPSTREAM_UNAVAILABLE", "upstream unavailable").SetRetryable(true).SetTemporary(true).SetSeverity(poco.SeverityWarning)`,
+		} {
+			if !strings.Contains(src, expected) {
+				t.Errorf("source should contain %q, got\n%s", expected, src)
+			}
+		}
+	})
+
+	t.Run("should comment every description line", func(t *testing.T) {
+		c, err := catalog.Parse([]byte(`
+package: users
+errors:
+  - code: USER_BANNED
+    message: user is banned
+    description: |
+      The user was banned.
+      var second line
+
+      Contact support.
+`))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		b, err := catalog.GenerateGo(c)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !strings.Contains(string(b), "\t// The user was banned.\n\t// var second line\n\t//\n\t// Contact support.\n") {
+			t.Errorf("every description line should be commented, got\n%s", b)
+		}
+	})
+
+	t.Run("should generate markdown", func(t *testing.T) {
+		b, err := catalog.GenerateMarkdown(c)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		md := string(b)
+
+		for _, expected := range []string{
+			"| `USER_NOT_FOUND` | 404 Not Found | NOT_FOUND | no | user not found |",
+			"## UPSTREAM_UNAVAILABLE",
+			"- Severity: warning",
+			"The requested user does not exist.",
+			"| `user_id` | string |",
+		} {
+			if !strings.Contains(md, expected) {
+				t.Errorf("markdown should contain %q, got\n%s", expected, md)
+			}
+		}
+	})
+
+	t.Run("should generate JSON", func(t *testing.T) {
+		b, err := catalog.GenerateJSON(c)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var decoded catalog.Catalog
+
+		if err := json.Unmarshal(b, &decoded); err != nil {
+			t.Fatal(err)
+		}
+
+		if len(decoded.Errors) != 3 || decoded.Errors[2].Retryable != true {
+			t.Errorf("unexpected reference %s", b)
+		}
+	})
+}
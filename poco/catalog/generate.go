@@ -0,0 +1,148 @@
+package catalog
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"net/http"
+	"strings"
+	"text/template"
+)
+
+var goTemplate = template.Must(template.New("go").Funcs(template.FuncMap{
+	"grpc":     grpcConstant,
+	"severity": func(s string) string { return severities[s] },
+	"field":    camelCase,
+	"quote":    func(s string) string { return fmt.Sprintf("%q", s) },
+	"comment": func(s string) string {
+		lines := strings.Split(strings.TrimSpace(s), "\n")
+
+		for i, line := range lines {
+			lines[i] = strings.TrimRight("\t// "+line, " \t\r")
+		}
+
+		return strings.Join(lines, "\n")
+	},
+	"needsTime": func(c Catalog) bool {
+		for _, e := range c.Errors {
+			for _, f := range e.Fields() {
+				if strings.HasPrefix(f.Type, "time.") {
+					return true
+				}
+			}
+		}
+
+		return false
+	},
+}).Parse(`// Code generated by pococatalog. DO NOT EDIT.
+
+package {{ .Package }}
+
+import (
+{{- if needsTime . }}
+	"time"
+{{ end }}
+	"github.com/Arsfiqball/talker/poco"
+)
+
+var (
+{{- range .Errors }}
+	// {{ .Name }} is the {{ .Code }} error.{{ if .Description }}
+{{ comment .Description }}{{ end }}
+	{{ .Name }} = {{ if .DataType }}poco.DefineError[{{ .DataType }}]{{ else }}poco.NewError{{ end }}({{ quote .Code }}, {{ quote .Message }})
+		{{- if .Retryable }}.SetRetryable(true){{ end }}
+		{{- if .Temporary }}.SetTemporary(true){{ end }}
+		{{- if .UserVisible }}.SetUserVisible(true){{ end }}
+		{{- if .Severity }}.SetSeverity({{ severity .Severity }}){{ end }}
+{{- end }}
+)
+{{ range .Errors }}{{ if .DataType }}
+// {{ .DataType }} is the data carried by {{ .Name }}.
+type {{ .DataType }} struct {
+{{- range .Fields }}
+	{{ field .Name }} {{ .Type }} ` + "`json:\"{{ .Name }}\"`" + `
+{{- end }}
+}
+{{ end }}{{ end }}
+// RegisterStatuses maps the errors of the catalog to their transport status.
+func RegisterStatuses(registry *poco.StatusRegistry) {
+{{- range .Errors }}
+	registry.Register({{ .Name }}, poco.Status{HTTP: {{ .HTTP }}, GRPC: {{ grpc .GRPC }}{{ if .UserVisible }}, Message: {{ quote .Message }}{{ end }}})
+{{- end }}
+}
+`))
+
+// GenerateGo returns the Go source declaring the sentinels, with their
+// classification, the data types and the status registration of the catalog.
+// Only the message of a user visible error is registered as public message.
+func GenerateGo(c Catalog) ([]byte, error) {
+	var buf bytes.Buffer
+
+	if err := goTemplate.Execute(&buf, c); err != nil {
+		return nil, err
+	}
+
+	return format.Source(buf.Bytes())
+}
+
+var markdownTemplate = template.Must(template.New("md").Funcs(template.FuncMap{
+	"statusText": http.StatusText,
+	"yesNo": func(b bool) string {
+		if b {
+			return "yes"
+		}
+
+		return "no"
+	},
+}).Parse(`# Error Reference
+
+| Code | HTTP | gRPC | Retryable | Message |
+| ---- | ---- | ---- | --------- | ------- |
+{{- range .Errors }}
+| ` + "`{{ .Code }}`" + ` | {{ .HTTP }} {{ statusText .HTTP }} | {{ .GRPC }} | {{ yesNo .Retryable }} | {{ .Message }} |
+{{- end }}
+{{ range .Errors }}
+## {{ .Code }}
+
+{{ if .Description }}{{ .Description }}
+
+{{ end }}- Message: {{ .Message }}
+- HTTP status: {{ .HTTP }} {{ statusText .HTTP }}
+- gRPC code: {{ .GRPC }}
+- Retryable: {{ yesNo .Retryable }}
+- Temporary: {{ yesNo .Temporary }}
+- User visible: {{ yesNo .UserVisible }}
+{{- if .Severity }}
+- Severity: {{ .Severity }}
+{{- end }}
+{{- if .Data }}
+
+| Data field | Type |
+| ---------- | ---- |
+{{- range .Fields }}
+| ` + "`{{ .Name }}`" + ` | {{ .Type }} |
+{{- end }}
+{{- end }}
+{{ end }}`))
+
+// GenerateMarkdown returns a markdown reference of the catalog for API consumers.
+func GenerateMarkdown(c Catalog) ([]byte, error) {
+	var buf bytes.Buffer
+
+	if err := markdownTemplate.Execute(&buf, c); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// GenerateJSON returns a JSON reference of the catalog for API consumers.
+func GenerateJSON(c Catalog) ([]byte, error) {
+	b, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append(b, '\n'), nil
+}
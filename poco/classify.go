@@ -0,0 +1,224 @@
+package poco
+
+// flag is a tri-state classification flag, so a wrapping error can override
+// the classification of its parents in both directions.
+type flag uint8
+
+const (
+	flagUnset flag = iota
+	flagTrue
+	flagFalse
+)
+
+func toFlag(b bool) flag {
+	if b {
+		return flagTrue
+	}
+
+	return flagFalse
+}
+
+func (f flag) bool() *bool {
+	if f == flagUnset {
+		return nil
+	}
+
+	b := f == flagTrue
+
+	return &b
+}
+
+func flagOf(b *bool) flag {
+	if b == nil {
+		return flagUnset
+	}
+
+	return toFlag(*b)
+}
+
+// classification holds the classification flags of an Error.
+type classification struct {
+	retryable   flag
+	temporary   flag
+	userVisible flag
+	severity    Severity
+}
+
+// Severity tells how urgent an error is, e.g. whether it should alert.
+type Severity uint8
+
+const (
+	SeverityUnset Severity = iota
+	SeverityInfo
+	SeverityWarning
+	SeverityError
+	SeverityCritical
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityInfo:
+		return "info"
+	case SeverityWarning:
+		return "warning"
+	case SeverityError:
+		return "error"
+	case SeverityCritical:
+		return "critical"
+	default:
+		return "unset"
+	}
+}
+
+func parseSeverity(s string) Severity {
+	for severity := SeverityInfo; severity <= SeverityCritical; severity++ {
+		if severity.String() == s {
+			return severity
+		}
+	}
+
+	return SeverityUnset
+}
+
+// SetRetryable marks the error as worth retrying, or not.
+func (e Error) SetRetryable(retryable bool) Error {
+	e.class.retryable = toFlag(retryable)
+	return e
+}
+
+// SetTemporary marks the error as transient, or not.
+func (e Error) SetTemporary(temporary bool) Error {
+	e.class.temporary = toFlag(temporary)
+	return e
+}
+
+// SetUserVisible marks the message of the error as safe to show to users, or not.
+func (e Error) SetUserVisible(userVisible bool) Error {
+	e.class.userVisible = toFlag(userVisible)
+	return e
+}
+
+func (e Error) SetSeverity(severity Severity) Error {
+	e.class.severity = severity
+	return e
+}
+
+func (d ErrorDef[T]) SetRetryable(retryable bool) ErrorDef[T] {
+	d.err = d.err.SetRetryable(retryable)
+	return d
+}
+
+func (d ErrorDef[T]) SetTemporary(temporary bool) ErrorDef[T] {
+	d.err = d.err.SetTemporary(temporary)
+	return d
+}
+
+func (d ErrorDef[T]) SetUserVisible(userVisible bool) ErrorDef[T] {
+	d.err = d.err.SetUserVisible(userVisible)
+	return d
+}
+
+func (d ErrorDef[T]) SetSeverity(severity Severity) ErrorDef[T] {
+	d.err = d.err.SetSeverity(severity)
+	return d
+}
+
+// findFlag returns the flag of the outermost error of the chain of err which
+// sets it. Errors which are not a poco.Error are asked with check.
+func findFlag(err error, get func(Error) flag, check func(error) flag) bool {
+	result := flagUnset
+
+	walkError(err, func(e error) bool {
+		if pocoErr, ok := e.(Error); ok {
+			result = get(pocoErr)
+		} else if check != nil {
+			result = check(e)
+		}
+
+		return result == flagUnset
+	})
+
+	return result == flagTrue
+}
+
+// IsRetryable reports whether the outermost error of the chain of err setting
+// the retryable flag marks it as retryable. Errors implementing
+// Retryable() bool are asked too.
+func IsRetryable(err error) bool {
+	return findFlag(err, func(e Error) flag {
+		return e.class.retryable
+	}, func(e error) flag {
+		if r, ok := e.(interface{ Retryable() bool }); ok {
+			return toFlag(r.Retryable())
+		}
+
+		return flagUnset
+	})
+}
+
+// IsTemporary reports whether the outermost error of the chain of err setting
+// the temporary flag marks it as temporary. Errors implementing
+// Temporary() bool, like net.Error, are asked too.
+func IsTemporary(err error) bool {
+	return findFlag(err, func(e Error) flag {
+		return e.class.temporary
+	}, func(e error) flag {
+		if t, ok := e.(interface{ Temporary() bool }); ok {
+			return toFlag(t.Temporary())
+		}
+
+		return flagUnset
+	})
+}
+
+// IsUserVisible reports whether the outermost error of the chain of err
+// setting the user visible flag marks it as user visible.
+func IsUserVisible(err error) bool {
+	return findFlag(err, func(e Error) flag {
+		return e.class.userVisible
+	}, nil)
+}
+
+// UserMessage returns the message of the outermost poco.Error of the chain of
+// err marked as user visible, and whether there is one.
+func UserMessage(err error) (string, bool) {
+	var (
+		message string
+		found   bool
+	)
+
+	walkError(err, func(e error) bool {
+		if pocoErr, ok := e.(Error); ok && pocoErr.class.userVisible != flagUnset {
+			if pocoErr.class.userVisible == flagTrue {
+				message, found = pocoErr.message, true
+			}
+
+			return false
+		}
+
+		return true
+	})
+
+	return message, found
+}
+
+// ErrorSeverity returns the severity of the outermost error of the chain of err
+// setting it, SeverityError when none does and SeverityUnset for a nil error.
+func ErrorSeverity(err error) Severity {
+	if err == nil {
+		return SeverityUnset
+	}
+
+	severity := SeverityError
+
+	walkError(err, func(e error) bool {
+		if pocoErr, ok := e.(Error); ok && pocoErr.class.severity != SeverityUnset {
+			severity = pocoErr.class.severity
+			return false
+		}
+
+		return true
+	})
+
+	return severity
+}
@@ -0,0 +1,122 @@
+package poco_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/Arsfiqball/talker/poco"
+)
+
+type temporaryError struct{}
+
+func (temporaryError) Error() string   { return "temporary" }
+func (temporaryError) Temporary() bool { return true }
+
+func TestClassification(t *testing.T) {
+	errUnavailable := poco.NewError("UNAVAILABLE", "service unavailable").
+		SetRetryable(true).
+		SetTemporary(true).
+		SetSeverity(poco.SeverityWarning)
+
+	errPayment := poco.NewError("PAYMENT_FAILED", "payment failed")
+
+	t.Run("should query flags across the chain", func(t *testing.T) {
+		err := fmt.Errorf("checkout: %w", errPayment.Wrap(errUnavailable.Wrap()))
+
+		if !poco.IsRetryable(err) || !poco.IsTemporary(err) {
+			t.Error("flags of the cause should be found")
+		}
+
+		if poco.ErrorSeverity(err) != poco.SeverityWarning {
+			t.Errorf("severity should be warning, got %s", poco.ErrorSeverity(err))
+		}
+
+		if poco.IsUserVisible(err) {
+			t.Error("error should not be user visible")
+		}
+	})
+
+	t.Run("should let the outermost error override the flags", func(t *testing.T) {
+		err := errPayment.SetRetryable(false).SetSeverity(poco.SeverityCritical).Wrap(errUnavailable.Wrap())
+
+		if poco.IsRetryable(err) {
+			t.Error("outer error should disable retries")
+		}
+
+		if !poco.IsTemporary(err) {
+			t.Error("unset flag should be taken from the cause")
+		}
+
+		if poco.ErrorSeverity(err) != poco.SeverityCritical {
+			t.Errorf("severity should be critical, got %s", poco.ErrorSeverity(err))
+		}
+	})
+
+	t.Run("should find flags in multiple causes", func(t *testing.T) {
+		err := errPayment.Wrap(errors.New("first"), errUnavailable)
+
+		if !poco.IsRetryable(err) {
+			t.Error("flag of the second cause should be found")
+		}
+	})
+
+	t.Run("should ask errors implementing Temporary", func(t *testing.T) {
+		if !poco.IsTemporary(errPayment.Wrap(temporaryError{})) {
+			t.Error("temporary error should be found")
+		}
+	})
+
+	t.Run("should default severity", func(t *testing.T) {
+		if poco.ErrorSeverity(errPayment) != poco.SeverityError {
+			t.Error("severity should default to error")
+		}
+
+		if poco.ErrorSeverity(nil) != poco.SeverityUnset {
+			t.Error("nil error should have no severity")
+		}
+
+		if poco.IsRetryable(nil) || poco.IsRetryable(context.Canceled) {
+			t.Error("unclassified errors should not be retryable")
+		}
+	})
+
+	t.Run("should expose the message of user visible errors", func(t *testing.T) {
+		err := errPayment.Wrap(poco.NewError("CARD_DECLINED", "card declined").SetUserVisible(true))
+
+		if message, ok := poco.UserMessage(err); !ok || message != "card declined" {
+			t.Errorf("user message should be found, got %q, %v", message, ok)
+		}
+
+		if _, ok := poco.UserMessage(errPayment.SetUserVisible(false).Wrap(err)); ok {
+			t.Error("outer error should hide the user message")
+		}
+	})
+
+	t.Run("should classify typed definitions", func(t *testing.T) {
+		def := poco.DefineError[int]("RATE_LIMITED", "rate limited").SetRetryable(true)
+
+		if !poco.IsRetryable(def.New(3)) {
+			t.Error("error of the definition should be retryable")
+		}
+	})
+
+	t.Run("should keep flags through JSON", func(t *testing.T) {
+		b, err := json.Marshal(errPayment.SetRetryable(false).Wrap(errUnavailable))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var remote poco.Error
+
+		if err := json.Unmarshal(b, &remote); err != nil {
+			t.Fatal(err)
+		}
+
+		if poco.IsRetryable(remote) || !poco.IsTemporary(remote) || poco.ErrorSeverity(remote) != poco.SeverityWarning {
+			t.Errorf("flags should be kept, got %s", b)
+		}
+	})
+}
@@ -0,0 +1,70 @@
+// Command pococatalog generates the poco error declarations and the error
+// reference of a package from its error catalog.
+//
+// Usage:
+//
+//	//go:generate go run github.com/Arsfiqball/talker/poco/cmd/pococatalog -in errors.yaml -go errors_gen.go -md errors.md
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/Arsfiqball/talker/poco/catalog"
+)
+
+func main() {
+	if err := run(os.Args[1:]); err != nil {
+		fmt.Fprintln(os.Stderr, "pococatalog:", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string) error {
+	fs := flag.NewFlagSet("pococatalog", flag.ContinueOnError)
+
+	in := fs.String("in", "errors.yaml", "catalog file (YAML or JSON)")
+	goOut := fs.String("go", "", "output file of the Go declarations")
+	mdOut := fs.String("md", "", "output file of the markdown reference")
+	jsonOut := fs.String("json", "", "output file of the JSON reference")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *goOut == "" && *mdOut == "" && *jsonOut == "" {
+		return fmt.Errorf("at least one of -go, -md or -json is required")
+	}
+
+	c, err := catalog.Load(*in)
+	if err != nil {
+		return err
+	}
+
+	outputs := []struct {
+		path     string
+		generate func(catalog.Catalog) ([]byte, error)
+	}{
+		{*goOut, catalog.GenerateGo},
+		{*mdOut, catalog.GenerateMarkdown},
+		{*jsonOut, catalog.GenerateJSON},
+	}
+
+	for _, out := range outputs {
+		if out.path == "" {
+			continue
+		}
+
+		b, err := out.generate(c)
+		if err != nil {
+			return err
+		}
+
+		if err := os.WriteFile(out.path, b, 0o644); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	t.Run("should write the requested outputs", func(t *testing.T) {
+		dir := t.TempDir()
+		in := filepath.Join(dir, "errors.yaml")
+
+		if err := os.WriteFile(in, []byte("package: users\nerrors:\n  - code: USER_NOT_FOUND\n    message: user not found\n    http: 404\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		goOut := filepath.Join(dir, "errors_gen.go")
+		mdOut := filepath.Join(dir, "errors.md")
+
+		if err := run([]string{"-in", in, "-go", goOut, "-md", mdOut}); err != nil {
+			t.Fatal(err)
+		}
+
+		b, err := os.ReadFile(goOut)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !strings.Contains(string(b), "ErrUserNotFound") {
+			t.Errorf("unexpected Go output\n%s", b)
+		}
+
+		if _, err := os.Stat(mdOut); err != nil {
+			t.Error(err)
+		}
+
+		if _, err := os.Stat(filepath.Join(dir, "errors.json")); !os.IsNotExist(err) {
+			t.Error("JSON reference should not be written")
+		}
+	})
+
+	t.Run("should require an output", func(t *testing.T) {
+		if err := run([]string{"-in", "errors.yaml"}); err == nil {
+			t.Error("should fail without output")
+		}
+	})
+}
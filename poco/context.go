@@ -0,0 +1,47 @@
+package poco
+
+import "context"
+
+type observerKey struct{}
+
+// WithObserver returns a copy of ctx carrying obs, used by the package level
+// Span, StartSpan, Event and Err functions.
+func WithObserver(ctx context.Context, obs *Observer) context.Context {
+	return context.WithValue(ctx, observerKey{}, obs)
+}
+
+// ObserverFrom returns the observer carried by ctx, or nil.
+func ObserverFrom(ctx context.Context) *Observer {
+	obs, _ := ctx.Value(observerKey{}).(*Observer)
+	return obs
+}
+
+func Span(ctx context.Context, name string, attrs []any) (context.Context, SpanEnd) {
+	if obs := ObserverFrom(ctx); obs != nil {
+		return obs.Span(ctx, name, attrs)
+	}
+
+	return ctx, func() {}
+}
+
+func StartSpan(ctx context.Context, name string, attrs []any) (context.Context, *SpanHandle) {
+	if obs := ObserverFrom(ctx); obs != nil {
+		return obs.StartSpan(ctx, name, attrs)
+	}
+
+	return ctx, nil
+}
+
+func Event(ctx context.Context, name string, attrs []any) {
+	if obs := ObserverFrom(ctx); obs != nil {
+		obs.Event(ctx, name, attrs)
+	}
+}
+
+func Err(ctx context.Context, err error) error {
+	if obs := ObserverFrom(ctx); obs != nil {
+		return obs.Error(ctx, err)
+	}
+
+	return err
+}
@@ -0,0 +1,74 @@
+package poco_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Arsfiqball/talker/poco"
+)
+
+type countingListener struct {
+	spans  int
+	events int
+	errs   int
+}
+
+func (l *countingListener) OnSpan(ctx context.Context, name string, attrs []any) (context.Context, poco.SpanEnd) {
+	l.spans++
+	return ctx, func() {}
+}
+
+func (l *countingListener) OnEvent(ctx context.Context, name string, attrs []any) {
+	l.events++
+}
+
+func (l *countingListener) OnError(ctx context.Context, err error) error {
+	l.errs++
+	return err
+}
+
+func TestContextObserver(t *testing.T) {
+	t.Run("use observer from context", func(t *testing.T) {
+		listener := &countingListener{}
+		obs := poco.NewObserver(poco.WithListener(listener))
+		ctx := poco.WithObserver(context.Background(), obs)
+
+		if poco.ObserverFrom(ctx) != obs {
+			t.Fatal("observer should be found in context")
+		}
+
+		ctx, end := poco.Span(ctx, "span", nil)
+		_, span := poco.StartSpan(ctx, "child", nil)
+		span.End()
+		poco.Event(ctx, "event", nil)
+		_ = poco.Err(ctx, errors.New("test"))
+		end()
+
+		if listener.spans != 2 || listener.events != 1 || listener.errs != 1 {
+			t.Errorf("unexpected counts %+v", listener)
+		}
+	})
+
+	t.Run("no-op without observer", func(t *testing.T) {
+		ctx := context.Background()
+		errTest := errors.New("test")
+
+		spanCtx, end := poco.Span(ctx, "span", nil)
+		end()
+
+		if spanCtx != ctx {
+			t.Error("context should be unchanged")
+		}
+
+		_, span := poco.StartSpan(ctx, "span", nil)
+		span.SetAttributes([]any{"a", 1})
+		span.End()
+
+		poco.Event(ctx, "event", nil)
+
+		if err := poco.Err(ctx, errTest); err != errTest {
+			t.Errorf("error should be unchanged, got %v", err)
+		}
+	})
+}
@@ -1,9 +1,11 @@
 package poco
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"runtime"
+	"strings"
 )
 
 type Error struct {
@@ -13,6 +15,8 @@ type Error struct {
 	wrappedAt  string
 	data       interface{}
 	parent     error
+	stack      *callStack
+	class      classification
 }
 
 func NewError(code string, defaultMessage string) Error {
@@ -27,21 +31,61 @@ func NewError(code string, defaultMessage string) Error {
 	return Error{code: code, message: defaultMessage, declaredAt: caller}
 }
 
-func (e Error) Wrap(err error) Error {
+// causes holds the parents of an Error wrapping several errors. It is kept
+// behind a pointer so Error stays comparable.
+type causes struct {
+	errs []error
+}
+
+func (c *causes) Error() string {
+	return errors.Join(c.errs...).Error()
+}
+
+func joinParents(errs []error) error {
+	parents := []error{}
+
+	for _, err := range errs {
+		if err != nil {
+			parents = append(parents, err)
+		}
+	}
+
+	switch len(parents) {
+	case 0:
+		return nil
+	case 1:
+		return parents[0]
+	}
+
+	return &causes{errs: parents}
+}
+
+func (e Error) Wrap(errs ...error) Error {
+	return e.wrap(1, errs)
+}
+
+// wrap sets the parents of e and records the location skip frames above the
+// caller of wrap.
+func (e Error) wrap(skip int, errs []error) Error {
 	var caller string
 
-	_, file, line, ok := runtime.Caller(1)
+	_, file, line, ok := runtime.Caller(skip + 1)
 
 	if ok {
 		caller = fmt.Sprintf("%s:%d", file, line)
 	}
 
-	e.parent = err
+	e.parent = joinParents(errs)
 	e.wrappedAt = caller
+	e.stack = captureStack(skip + 1)
 
 	return e
 }
 
+func (e Error) Code() string {
+	return e.code
+}
+
 func (e Error) SetInfo(message string) Error {
 	e.message = message
 
@@ -62,14 +106,23 @@ func (e Error) Data() interface{} {
 	return e.data
 }
 
+// sentinel is implemented by the values matched by code: Error and ErrorDef.
+type sentinel interface {
+	sentinel() Error
+}
+
+func (e Error) sentinel() Error {
+	return e
+}
+
 func (e Error) Is(target error) bool {
 	if target == nil {
 		return false
 	}
 
-	pocoErr, ok := target.(Error)
+	s, ok := target.(sentinel)
 
-	if ok && e.code == pocoErr.code {
+	if ok && e.code == s.sentinel().code {
 		return true
 	}
 
@@ -80,32 +133,97 @@ func (e Error) Error() string {
 	return e.message
 }
 
-func (e Error) Unwrap() error {
-	return e.parent
+func (e Error) Unwrap() []error {
+	switch parent := e.parent.(type) {
+	case nil:
+		return nil
+	case *causes:
+		return parent.errs
+	default:
+		return []error{parent}
+	}
+}
+
+func unwrapAll(err error) []error {
+	switch e := err.(type) {
+	case interface{ Unwrap() []error }:
+		return e.Unwrap()
+	case interface{ Unwrap() error }:
+		if parent := e.Unwrap(); parent != nil {
+			return []error{parent}
+		}
+	}
+
+	return nil
+}
+
+// walkError calls fn for err and each error of its tree, depth first and
+// outermost first, until fn returns false.
+func walkError(err error, fn func(error) bool) bool {
+	if err == nil {
+		return true
+	}
+
+	if !fn(err) {
+		return false
+	}
+
+	for _, parent := range unwrapAll(err) {
+		if !walkError(parent, fn) {
+			return false
+		}
+	}
+
+	return true
 }
 
+// TraceError renders the tree of err, one line per error. The children of an
+// error wrapping several errors are indented by two spaces, while a single
+// parent stays at the same level.
 func TraceError(err error) []string {
 	var result []string
 
+	traceError(err, 0, &result)
+
+	return result
+}
+
+func traceError(err error, depth int, result *[]string) {
 	for err != nil {
-		pocoErr, ok := err.(Error)
+		var line string
 
-		if ok {
-			place := pocoErr.declaredAt
+		switch e := err.(type) {
+		case Error:
+			place := e.declaredAt
 
-			if pocoErr.wrappedAt != "" {
-				place = pocoErr.wrappedAt
+			if e.wrappedAt != "" {
+				place = e.wrappedAt
 			}
 
-			result = append(result, fmt.Sprintf("%s at %s: %s", pocoErr.code, pocoErr.message, place))
-			err = pocoErr.parent
-		} else {
-			result = append(result, fmt.Sprintf("sentinel: %s", err))
-			err = nil
+			line = fmt.Sprintf("%s at %s: %s", e.code, e.message, place)
+		case interface{ Unwrap() []error }:
+			line = "joined:"
+		case interface{ Unwrap() error }:
+			line = fmt.Sprintf("wrapped: %s", err)
+		default:
+			line = fmt.Sprintf("sentinel: %s", err)
 		}
-	}
 
-	return result
+		*result = append(*result, strings.Repeat("  ", depth)+line)
+
+		parents := unwrapAll(err)
+
+		if len(parents) == 1 {
+			err = parents[0]
+			continue
+		}
+
+		for _, parent := range parents {
+			traceError(parent, depth+1, result)
+		}
+
+		return
+	}
 }
 
 func ErrorIsOneOf(err error, targets ...error) bool {
@@ -117,3 +235,164 @@ func ErrorIsOneOf(err error, targets ...error) bool {
 
 	return false
 }
+
+type jsonError struct {
+	Code        string       `json:"code,omitempty"`
+	Message     string       `json:"message,omitempty"`
+	Data        interface{}  `json:"data,omitempty"`
+	Retryable   *bool        `json:"retryable,omitempty"`
+	Temporary   *bool        `json:"temporary,omitempty"`
+	UserVisible *bool        `json:"user_visible,omitempty"`
+	Severity    string       `json:"severity,omitempty"`
+	Sentinel    string       `json:"sentinel,omitempty"`
+	Wrapped     string       `json:"wrapped,omitempty"`
+	Cause       *jsonError   `json:"cause,omitempty"`
+	Causes      []*jsonError `json:"causes,omitempty"`
+}
+
+func toJSONError(err error) *jsonError {
+	if err == nil {
+		return nil
+	}
+
+	pocoErr, ok := err.(Error)
+
+	if !ok {
+		parents := unwrapAll(err)
+
+		if len(parents) == 0 {
+			return &jsonError{Sentinel: err.Error()}
+		}
+
+		j := &jsonError{Wrapped: err.Error()}
+		j.setCauses(parents)
+
+		return j
+	}
+
+	j := &jsonError{
+		Code:        pocoErr.code,
+		Message:     pocoErr.message,
+		Data:        pocoErr.data,
+		Retryable:   pocoErr.class.retryable.bool(),
+		Temporary:   pocoErr.class.temporary.bool(),
+		UserVisible: pocoErr.class.userVisible.bool(),
+	}
+
+	if pocoErr.class.severity != SeverityUnset {
+		j.Severity = pocoErr.class.severity.String()
+	}
+
+	j.setCauses(pocoErr.Unwrap())
+
+	return j
+}
+
+func (j *jsonError) setCauses(parents []error) {
+	if len(parents) == 1 {
+		j.Cause = toJSONError(parents[0])
+		return
+	}
+
+	for _, parent := range parents {
+		j.Causes = append(j.Causes, toJSONError(parent))
+	}
+}
+
+func (j *jsonError) causes() []error {
+	parents := []error{}
+
+	if j.Cause != nil {
+		parents = append(parents, fromJSONError(j.Cause))
+	}
+
+	for _, cause := range j.Causes {
+		parents = append(parents, fromJSONError(cause))
+	}
+
+	return parents
+}
+
+// wrappedError is an error of fmt.Errorf with %w, or another wrapper than
+// Error, decoded from JSON.
+type wrappedError struct {
+	message string
+	parent  error
+}
+
+func (e *wrappedError) Error() string {
+	return e.message
+}
+
+func (e *wrappedError) Unwrap() error {
+	return e.parent
+}
+
+// joinedError is an error of errors.Join, or another wrapper of several
+// errors, decoded from JSON.
+type joinedError struct {
+	message string
+	parents []error
+}
+
+func (e *joinedError) Error() string {
+	return e.message
+}
+
+func (e *joinedError) Unwrap() []error {
+	return e.parents
+}
+
+func fromJSONError(j *jsonError) error {
+	if j == nil {
+		return nil
+	}
+
+	if j.Code == "" {
+		switch parents := j.causes(); len(parents) {
+		case 0:
+		case 1:
+			return &wrappedError{message: j.Wrapped, parent: parents[0]}
+		default:
+			return &joinedError{message: j.Wrapped, parents: parents}
+		}
+
+		return errors.New(j.Sentinel)
+	}
+
+	e := Error{
+		code:    j.Code,
+		message: j.Message,
+		data:    j.Data,
+		class: classification{
+			retryable:   flagOf(j.Retryable),
+			temporary:   flagOf(j.Temporary),
+			userVisible: flagOf(j.UserVisible),
+			severity:    parseSeverity(j.Severity),
+		},
+	}
+
+	e.parent = joinParents(j.causes())
+
+	return e
+}
+
+func (e Error) MarshalJSON() ([]byte, error) {
+	return json.Marshal(toJSONError(e))
+}
+
+func (e *Error) UnmarshalJSON(b []byte) error {
+	var j jsonError
+
+	if err := json.Unmarshal(b, &j); err != nil {
+		return err
+	}
+
+	if j.Code == "" {
+		return errors.New("poco: error code is missing")
+	}
+
+	*e = fromJSONError(&j).(Error)
+
+	return nil
+}
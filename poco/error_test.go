@@ -1,6 +1,9 @@
 package poco_test
 
 import (
+	"encoding/json"
+	"fmt"
+	"strings"
 	"testing"
 
 	"errors"
@@ -106,3 +109,152 @@ func TestErrorIsOneOf(t *testing.T) {
 		}
 	})
 }
+
+func TestErrorJSON(t *testing.T) {
+	t.Run("round trip with parent chain", func(t *testing.T) {
+		errNotFound := poco.NewError("NOT_FOUND", "not found")
+		errFetch := poco.NewError("FETCH_FAILED", "fetch failed")
+
+		err := errFetch.Wrap(errNotFound.SetData(map[string]interface{}{"id": "42"}).Wrap(errors.New("sql: no rows")))
+
+		b, jsonErr := json.Marshal(err)
+		if jsonErr != nil {
+			t.Fatal(jsonErr)
+		}
+
+		expected := `{"code":"FETCH_FAILED","message":"fetch failed","cause":{"code":"NOT_FOUND","message":"not found","data":{"id":"42"},"cause":{"sentinel":"sql: no rows"}}}`
+
+		if string(b) != expected {
+			t.Fatalf("json should be %s, got %s", expected, b)
+		}
+
+		var remote poco.Error
+
+		if jsonErr := json.Unmarshal(b, &remote); jsonErr != nil {
+			t.Fatal(jsonErr)
+		}
+
+		if !errors.Is(remote, errFetch) || !errors.Is(remote, errNotFound) {
+			t.Error("remote error should match local sentinels")
+		}
+
+		var remoteNotFound poco.Error
+
+		if !errors.As(remote.Unwrap()[0], &remoteNotFound) {
+			t.Fatal("parent should be a poco error")
+		}
+
+		if data, _ := remoteNotFound.Data().(map[string]interface{}); data["id"] != "42" {
+			t.Errorf("data should be restored, got %v", remoteNotFound.Data())
+		}
+
+		if parents := remoteNotFound.Unwrap(); len(parents) != 1 || parents[0].Error() != "sql: no rows" {
+			t.Errorf("sentinel should be restored, got %v", remoteNotFound.Unwrap())
+		}
+	})
+
+	t.Run("missing code", func(t *testing.T) {
+		var remote poco.Error
+
+		if err := json.Unmarshal([]byte(`{"message":"test"}`), &remote); err == nil {
+			t.Error("error without code should be rejected")
+		}
+	})
+}
+
+func TestErrorMultipleCauses(t *testing.T) {
+	errStartup := poco.NewError("STARTUP", "startup failed")
+	errDB := poco.NewError("DB", "db failed")
+	errCache := errors.New("cache failed")
+
+	t.Run("wrap multiple causes", func(t *testing.T) {
+		err := errStartup.Wrap(errDB, nil, errCache)
+
+		if !errors.Is(err, errDB) || !errors.Is(err, errCache) {
+			t.Error("error should match every cause")
+		}
+
+		if len(err.Unwrap()) != 2 {
+			t.Errorf("nil causes should be dropped, got %v", err.Unwrap())
+		}
+	})
+
+	t.Run("trace tree", func(t *testing.T) {
+		errConfig := fmt.Errorf("loading config: %w", errDB.Wrap(errors.New("timeout")))
+		err := errStartup.Wrap(errors.Join(errConfig, errCache))
+
+		trace := poco.TraceError(err)
+		expected := []string{
+			"STARTUP at startup failed: ",
+			"joined:",
+			"  wrapped: loading config: db failed",
+			"  DB at db failed: ",
+			"  sentinel: timeout",
+			"  sentinel: cache failed",
+		}
+
+		if len(trace) != len(expected) {
+			t.Fatalf("trace should be %q, got %q", expected, trace)
+		}
+
+		for i := range expected {
+			if !strings.HasPrefix(trace[i], expected[i]) {
+				t.Errorf("trace line %d should start with %q, got %q", i, expected[i], trace[i])
+			}
+		}
+	})
+
+	t.Run("json through wrappers", func(t *testing.T) {
+		errConfig := fmt.Errorf("loading config: %w", errDB.Wrap(errors.New("timeout")))
+		err := errStartup.Wrap(errors.Join(errConfig, errCache))
+
+		b, jsonErr := json.Marshal(err)
+		if jsonErr != nil {
+			t.Fatal(jsonErr)
+		}
+
+		var remote poco.Error
+
+		if jsonErr := json.Unmarshal(b, &remote); jsonErr != nil {
+			t.Fatal(jsonErr)
+		}
+
+		if !errors.Is(remote, errStartup) || !errors.Is(remote, errDB) {
+			t.Errorf("remote error should match local sentinels through wrappers, got %s", b)
+		}
+
+		if remote.Error() != err.Error() {
+			t.Errorf("message should be %q, got %q", err.Error(), remote.Error())
+		}
+
+		trace := poco.TraceError(remote)
+		expected := poco.TraceError(err)
+
+		if len(trace) != len(expected) {
+			t.Fatalf("trace should be %q, got %q", expected, trace)
+		}
+
+		for i := range expected {
+			if !strings.HasPrefix(expected[i], trace[i]) {
+				t.Errorf("trace line %d should match %q, got %q", i, expected[i], trace[i])
+			}
+		}
+	})
+
+	t.Run("json with multiple causes", func(t *testing.T) {
+		b, err := json.Marshal(errStartup.Wrap(errDB, errCache))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var remote poco.Error
+
+		if err := json.Unmarshal(b, &remote); err != nil {
+			t.Fatal(err)
+		}
+
+		if len(remote.Unwrap()) != 2 || !errors.Is(remote, errDB) {
+			t.Errorf("causes should be restored, got %v from %s", remote.Unwrap(), b)
+		}
+	})
+}
@@ -0,0 +1,95 @@
+package poco
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+var ErrGoexit = NewError("POCO_GOEXIT", "goroutine exited with runtime.Goexit")
+
+// spawn runs fn in a new goroutine and hands its outcome to done: the error
+// of fn, the RecoveredPanic of a panic, already reported to the observer of
+// ctx, or ErrGoexit when fn called runtime.Goexit.
+func spawn(ctx context.Context, fn func(context.Context) error, done func(error)) {
+	go func() {
+		var (
+			rp       RecoveredPanic
+			err      error
+			returned bool
+		)
+
+		defer func() {
+			switch {
+			case rp.Panicked():
+				done(rp)
+			case !returned:
+				done(ErrGoexit.Wrap())
+			default:
+				done(err)
+			}
+		}()
+
+		defer Recover(&rp, WithRecoverReport(ctx))
+
+		err = fn(ctx)
+		returned = true
+	}()
+}
+
+// Go runs fn in a new goroutine with ctx, so the span of ctx is the parent of
+// the spans started by fn. A panic is recovered and reported to the error
+// listeners of the observer carried by ctx. The returned channel receives the
+// outcome of fn, the RecoveredPanic of a panic, or ErrGoexit, and is closed.
+// Use context.WithoutCancel to keep fn running after ctx is canceled.
+func Go(ctx context.Context, fn func(context.Context) error) <-chan error {
+	result := make(chan error, 1)
+
+	spawn(ctx, fn, func(err error) {
+		result <- err
+		close(result)
+	})
+
+	return result
+}
+
+// TaskGroup runs goroutines like Go and waits for all of them.
+type TaskGroup struct {
+	ctx  context.Context
+	wg   sync.WaitGroup
+	mu   sync.Mutex
+	errs []error
+}
+
+// NewTaskGroup returns a group running its goroutines with ctx.
+func NewTaskGroup(ctx context.Context) *TaskGroup {
+	return &TaskGroup{ctx: ctx}
+}
+
+// Go runs fn in a new goroutine of the group.
+func (g *TaskGroup) Go(fn func(context.Context) error) {
+	g.wg.Add(1)
+
+	spawn(g.ctx, fn, func(err error) {
+		defer g.wg.Done()
+
+		if err == nil {
+			return
+		}
+
+		g.mu.Lock()
+		g.errs = append(g.errs, err)
+		g.mu.Unlock()
+	})
+}
+
+// Wait waits for the goroutines of the group and returns their errors
+// joined, including recovered panics.
+func (g *TaskGroup) Wait() error {
+	g.wg.Wait()
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return errors.Join(g.errs...)
+}
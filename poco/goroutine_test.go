@@ -0,0 +1,150 @@
+package poco_test
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"sync"
+	"testing"
+
+	"github.com/Arsfiqball/talker/poco"
+)
+
+type spanNameKey struct{}
+
+type goroutineListener struct {
+	mu   sync.Mutex
+	errs []error
+}
+
+func (l *goroutineListener) OnSpan(ctx context.Context, name string, attrs []any) (context.Context, poco.SpanEnd) {
+	return context.WithValue(ctx, spanNameKey{}, name), func() {}
+}
+
+func (l *goroutineListener) OnError(ctx context.Context, err error) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.errs = append(l.errs, err)
+
+	return err
+}
+
+func newGoroutineContext() (context.Context, *goroutineListener) {
+	listener := &goroutineListener{}
+	ctx := poco.WithObserver(context.Background(), poco.NewObserver(poco.WithListener(listener)))
+
+	return ctx, listener
+}
+
+func TestGo(t *testing.T) {
+	t.Run("should propagate the span and return the error", func(t *testing.T) {
+		ctx, _ := newGoroutineContext()
+		ctx, end := poco.Span(ctx, "parent", nil)
+		defer end()
+
+		errFailed := errors.New("failed")
+
+		err := <-poco.Go(ctx, func(ctx context.Context) error {
+			if ctx.Value(spanNameKey{}) != "parent" {
+				t.Error("span should be propagated")
+			}
+
+			return errFailed
+		})
+
+		if err != errFailed {
+			t.Errorf("error should be returned, got %v", err)
+		}
+	})
+
+	t.Run("should recover and report panics", func(t *testing.T) {
+		ctx, listener := newGoroutineContext()
+		errBoom := errors.New("boom")
+
+		err := <-poco.Go(ctx, func(ctx context.Context) error {
+			panic(errBoom)
+		})
+
+		var rp poco.RecoveredPanic
+
+		if !errors.As(err, &rp) || !errors.Is(err, errBoom) {
+			t.Fatalf("panic should be returned, got %v", err)
+		}
+
+		if len(listener.errs) != 1 || !errors.Is(listener.errs[0], errBoom) {
+			t.Errorf("panic should be reported, got %v", listener.errs)
+		}
+	})
+
+	t.Run("should report runtime.Goexit", func(t *testing.T) {
+		ctx, _ := newGoroutineContext()
+
+		err := <-poco.Go(ctx, func(ctx context.Context) error {
+			runtime.Goexit()
+			return nil
+		})
+
+		if !errors.Is(err, poco.ErrGoexit) {
+			t.Errorf("error should be ErrGoexit, got %v", err)
+		}
+	})
+}
+
+func TestTaskGroup(t *testing.T) {
+	t.Run("should wait for all goroutines and join errors", func(t *testing.T) {
+		ctx, listener := newGoroutineContext()
+		group := poco.NewTaskGroup(ctx)
+		errFailed := errors.New("failed")
+
+		var mu sync.Mutex
+
+		completed := 0
+
+		for i := 0; i < 5; i++ {
+			group.Go(func(ctx context.Context) error {
+				mu.Lock()
+				completed++
+				mu.Unlock()
+
+				return nil
+			})
+		}
+
+		group.Go(func(ctx context.Context) error {
+			return errFailed
+		})
+
+		group.Go(func(ctx context.Context) error {
+			panic("boom")
+		})
+
+		err := group.Wait()
+
+		if completed != 5 {
+			t.Errorf("all goroutines should complete, got %d", completed)
+		}
+
+		var rp poco.RecoveredPanic
+
+		if !errors.Is(err, errFailed) || !errors.As(err, &rp) || rp.Message() != "boom" {
+			t.Errorf("errors should be joined, got %v", err)
+		}
+
+		if len(listener.errs) != 1 {
+			t.Errorf("only the panic should be reported, got %v", listener.errs)
+		}
+	})
+
+	t.Run("should return nil without errors", func(t *testing.T) {
+		group := poco.NewTaskGroup(context.Background())
+
+		group.Go(func(ctx context.Context) error {
+			return nil
+		})
+
+		if err := group.Wait(); err != nil {
+			t.Error(err)
+		}
+	})
+}
@@ -0,0 +1,168 @@
+package poco
+
+import (
+	"context"
+	"net/http"
+)
+
+type httpConfig struct {
+	route    func(*http.Request) string
+	registry *StatusRegistry
+}
+
+type HTTPOption func(*httpConfig)
+
+// WithHTTPRoute sets the function naming the route of a request, which should
+// have a low cardinality like "/users/{id}". The URL path is used by default.
+func WithHTTPRoute(route func(*http.Request) string) HTTPOption {
+	return func(c *httpConfig) {
+		c.route = route
+	}
+}
+
+// WithHTTPStatusRegistry sets the registry rendering errors, DefaultStatusRegistry by default.
+func WithHTTPStatusRegistry(registry *StatusRegistry) HTTPOption {
+	return func(c *httpConfig) {
+		c.registry = registry
+	}
+}
+
+type statusRegistryKey struct{}
+
+func statusRegistryFrom(ctx context.Context) *StatusRegistry {
+	if registry, ok := ctx.Value(statusRegistryKey{}).(*StatusRegistry); ok {
+		return registry
+	}
+
+	return DefaultStatusRegistry
+}
+
+// HTTPMiddleware returns net/http middleware putting obs in the request ctx,
+// with the span context and baggage extracted from the headers, and
+// opening a span per request with the method, route and status code as
+// attributes. A panic of the handler is recovered, reported to the error
+// listeners of obs and rendered as problem response when nothing was written.
+// With a nil obs, the observer already carried by the request ctx, if any, is
+// used for panics and no span is opened.
+func HTTPMiddleware(obs *Observer, opts ...HTTPOption) func(http.Handler) http.Handler {
+	cfg := httpConfig{
+		route: func(r *http.Request) string {
+			return r.URL.Path
+		},
+		registry: DefaultStatusRegistry,
+	}
+
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+
+			if obs != nil {
+				ctx = WithObserver(ctx, obs)
+			}
+
+			ctx = context.WithValue(ctx, statusRegistryKey{}, cfg.registry)
+
+			ctx = Extract(ctx, r.Header)
+
+			route := cfg.route(r)
+
+			ctx, span := obs.StartSpan(ctx, r.Method+" "+route, []any{"http.method", r.Method, "http.route", route})
+
+			sw := &statusWriter{ResponseWriter: w}
+
+			var rp RecoveredPanic
+
+			defer func() {
+				// the panic is already reported to the error listeners, with the
+				// span ctx, by Recover
+				if rp.Panicked() && !sw.wroteHeader {
+					cfg.registry.WriteProblem(sw, r, rp)
+				}
+
+				status := sw.status
+
+				if status == 0 {
+					status = http.StatusOK
+				}
+
+				span.SetAttributes([]any{"http.status_code", status})
+
+				if status >= http.StatusInternalServerError {
+					span.SetStatus(SpanStatusError, http.StatusText(status))
+				}
+
+				span.End()
+			}()
+
+			defer Recover(&rp, WithRecoverReport(ctx))
+
+			next.ServeHTTP(sw, r.WithContext(ctx))
+		})
+	}
+}
+
+// HandlerFunc is an HTTP handler returning an error. A returned error is
+// rendered as application/problem+json response by the status registry of
+// HTTPMiddleware, or DefaultStatusRegistry, and reported to the observer of
+// the request ctx when its status is a server error.
+type HandlerFunc func(w http.ResponseWriter, r *http.Request) error
+
+func (f HandlerFunc) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	err := f(w, r)
+
+	if err == nil {
+		return
+	}
+
+	registry := statusRegistryFrom(r.Context())
+
+	if status, _ := registry.Status(err); status.HTTP >= http.StatusInternalServerError {
+		err = Err(r.Context(), err)
+	}
+
+	registry.WriteProblem(w, r, err)
+}
+
+// statusWriter records the status code written to the response.
+type statusWriter struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+}
+
+func (w *statusWriter) WriteHeader(status int) {
+	if !w.wroteHeader {
+		w.status = status
+		w.wroteHeader = true
+	}
+
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *statusWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	return w.ResponseWriter.Write(b)
+}
+
+// Flush implements http.Flusher when the wrapped writer does.
+func (w *statusWriter) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		if !w.wroteHeader {
+			w.WriteHeader(http.StatusOK)
+		}
+
+		flusher.Flush()
+	}
+}
+
+// Unwrap lets http.ResponseController reach the wrapped writer.
+func (w *statusWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,244 @@
+package poco_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/Arsfiqball/talker/poco"
+	"github.com/Arsfiqball/talker/poco/pocotest"
+)
+
+type httpSpan struct {
+	name   string
+	attrs  []any
+	status poco.SpanStatus
+	errs   []error
+	ended  bool
+}
+
+type httpListener struct {
+	mu    sync.Mutex
+	spans []*httpSpan
+	errs  []error
+}
+
+func (l *httpListener) OnSpanStart(ctx context.Context, name string, attrs []any) (context.Context, poco.SpanRecorder) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	span := &httpSpan{name: name, attrs: attrs}
+	l.spans = append(l.spans, span)
+
+	return ctx, &httpRecorder{listener: l, span: span}
+}
+
+func (l *httpListener) OnError(ctx context.Context, err error) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.errs = append(l.errs, err)
+
+	return err
+}
+
+type httpRecorder struct {
+	listener *httpListener
+	span     *httpSpan
+}
+
+func (r *httpRecorder) SetAttributes(attrs []any) {
+	r.span.attrs = append(r.span.attrs, attrs...)
+}
+
+func (r *httpRecorder) AddEvent(name string, attrs []any) {}
+
+func (r *httpRecorder) RecordError(err error) {
+	r.span.errs = append(r.span.errs, err)
+}
+
+func (r *httpRecorder) SetStatus(status poco.SpanStatus, description string) {
+	r.span.status = status
+}
+
+func (r *httpRecorder) End() {
+	r.span.ended = true
+}
+
+func attrValue(attrs []any, key string) any {
+	for i := 0; i+1 < len(attrs); i += 2 {
+		if attrs[i] == key {
+			return attrs[i+1]
+		}
+	}
+
+	return nil
+}
+
+func TestHTTPMiddleware(t *testing.T) {
+	errNotFound := poco.NewError("HTTP_USER_NOT_FOUND", "user not found")
+	errDatabase := poco.NewError("HTTP_DATABASE", "database is down")
+
+	registry := poco.NewStatusRegistry()
+	registry.Register(errNotFound, poco.Status{HTTP: http.StatusNotFound, Message: "user not found"})
+
+	setup := func(handler http.Handler) (*httpListener, http.Handler) {
+		listener := &httpListener{}
+		obs := poco.NewObserver(poco.WithListener(listener))
+		middleware := poco.HTTPMiddleware(obs,
+			poco.WithHTTPStatusRegistry(registry),
+			poco.WithHTTPRoute(func(r *http.Request) string { return "/users/{id}" }),
+		)
+
+		return listener, middleware(handler)
+	}
+
+	t.Run("should open a span per request", func(t *testing.T) {
+		listener, handler := setup(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if poco.ObserverFrom(r.Context()) == nil {
+				t.Error("observer should be in ctx")
+			}
+
+			w.WriteHeader(http.StatusCreated)
+		}))
+
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/users/42", nil))
+
+		if len(listener.spans) != 1 {
+			t.Fatalf("one span should be started, got %d", len(listener.spans))
+		}
+
+		span := listener.spans[0]
+
+		if span.name != "POST /users/{id}" || !span.ended {
+			t.Errorf("unexpected span %+v", span)
+		}
+
+		if attrValue(span.attrs, "http.method") != "POST" || attrValue(span.attrs, "http.route") != "/users/{id}" || attrValue(span.attrs, "http.status_code") != http.StatusCreated {
+			t.Errorf("unexpected attributes %v", span.attrs)
+		}
+
+		if span.status != poco.SpanStatusUnset {
+			t.Errorf("status should be unset, got %s", span.status)
+		}
+	})
+
+	t.Run("should propagate traceparent", func(t *testing.T) {
+		_, handler := setup(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			sc, ok := poco.SpanContextFrom(r.Context())
+
+			if !ok || sc.TraceID != "4bf92f3577b34da6a3ce929d0e0e4736" || sc.TraceState != "vendor=value" {
+				t.Errorf("span context should be extracted, got %+v", sc)
+			}
+		}))
+
+		req := httptest.NewRequest(http.MethodGet, "/users/42", nil)
+		req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+		req.Header.Set("tracestate", "vendor=value")
+
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+	})
+
+	t.Run("should recover and report panics", func(t *testing.T) {
+		rec := pocotest.NewRecorder()
+		handler := poco.HTTPMiddleware(rec.Observer())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			panic("boom")
+		}))
+
+		res := httptest.NewRecorder()
+
+		handler.ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/users/42", nil))
+
+		if res.Code != http.StatusInternalServerError || res.Header().Get("Content-Type") != "application/problem+json" {
+			t.Errorf("panic should be rendered as problem, got %d", res.Code)
+		}
+
+		if len(rec.Errors()) != 1 {
+			t.Errorf("panic should be recorded once, got %v", rec.Errors())
+		}
+
+		rec.AssertSpan(t, "GET /users/42").HasStatus(poco.SpanStatusError)
+	})
+
+	t.Run("should render returned errors", func(t *testing.T) {
+		listener, handler := setup(poco.HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+			if r.URL.Path == "/users/0" {
+				return errDatabase.Wrap(errors.New("connection refused"))
+			}
+
+			return errNotFound.Wrap()
+		}))
+
+		rec := httptest.NewRecorder()
+
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/users/42", nil))
+
+		var problem poco.Problem
+
+		if err := json.Unmarshal(rec.Body.Bytes(), &problem); err != nil {
+			t.Fatal(err)
+		}
+
+		if rec.Code != http.StatusNotFound || problem.Code != "HTTP_USER_NOT_FOUND" || problem.Detail != "user not found" {
+			t.Errorf("unexpected response %d %+v", rec.Code, problem)
+		}
+
+		if len(listener.errs) != 0 {
+			t.Errorf("client errors should not be reported, got %v", listener.errs)
+		}
+
+		rec = httptest.NewRecorder()
+
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/users/0", nil))
+
+		if rec.Code != http.StatusInternalServerError || len(listener.errs) != 1 {
+			t.Errorf("server errors should be reported, got %d %v", rec.Code, listener.errs)
+		}
+
+		if listener.spans[1].status != poco.SpanStatusError {
+			t.Errorf("span status should be error, got %s", listener.spans[1].status)
+		}
+	})
+
+	t.Run("should serve without observer", func(t *testing.T) {
+		handler := poco.HTTPMiddleware(nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/panic" {
+				panic("boom")
+			}
+
+			w.WriteHeader(http.StatusNoContent)
+		}))
+
+		rec := httptest.NewRecorder()
+
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		if rec.Code != http.StatusNoContent {
+			t.Errorf("status should be 204, got %d", rec.Code)
+		}
+
+		rec = httptest.NewRecorder()
+
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/panic", nil))
+
+		if rec.Code != http.StatusInternalServerError {
+			t.Errorf("panic should be rendered as 500, got %d", rec.Code)
+		}
+	})
+
+	t.Run("should render errors without middleware", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+
+		poco.HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+			return errors.New("unexpected")
+		}).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		if rec.Code != http.StatusInternalServerError {
+			t.Errorf("status should be 500, got %d", rec.Code)
+		}
+	})
+}
@@ -0,0 +1,210 @@
+package poco
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template"
+)
+
+type localeKey struct{}
+
+// WithLocale returns a copy of ctx carrying the preferred locales, most
+// preferred first.
+func WithLocale(ctx context.Context, locales ...string) context.Context {
+	return context.WithValue(ctx, localeKey{}, locales)
+}
+
+// LocaleFrom returns the preferred locales carried by ctx, or nil.
+func LocaleFrom(ctx context.Context) []string {
+	locales, _ := ctx.Value(localeKey{}).([]string)
+	return locales
+}
+
+// ParseAcceptLanguage returns the locales of an Accept-Language header value
+// ordered by quality, ignoring the wildcard and locales with quality 0.
+func ParseAcceptLanguage(header string) []string {
+	type weighted struct {
+		locale  string
+		quality float64
+	}
+
+	candidates := []weighted{}
+
+	for _, part := range strings.Split(header, ",") {
+		locale, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		locale = strings.TrimSpace(locale)
+		quality := 1.0
+
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(q, 64)
+			if err != nil {
+				continue
+			}
+
+			quality = parsed
+		}
+
+		if locale == "" || locale == "*" || quality <= 0 {
+			continue
+		}
+
+		candidates = append(candidates, weighted{locale: locale, quality: quality})
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].quality > candidates[j].quality
+	})
+
+	locales := make([]string, len(candidates))
+
+	for i, c := range candidates {
+		locales[i] = c.locale
+	}
+
+	return locales
+}
+
+// Localizer holds the translations of error messages keyed by error code and
+// locale. A translation is a text/template executed with the data of the
+// error, e.g. "user {{.UserID}} not found".
+type Localizer struct {
+	mu            sync.RWMutex
+	defaultLocale string
+	messages      map[string]map[string]*template.Template
+}
+
+// NewLocalizer returns an empty localizer, falling back to defaultLocale when
+// none of the preferred locales is translated.
+func NewLocalizer(defaultLocale string) *Localizer {
+	return &Localizer{
+		defaultLocale: normalizeLocale(defaultLocale),
+		messages:      map[string]map[string]*template.Template{},
+	}
+}
+
+// Add registers the translation of the code for the locale.
+func (l *Localizer) Add(locale string, code string, message string) error {
+	tmpl, err := template.New(code).Option("missingkey=error").Parse(message)
+	if err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.messages[code] == nil {
+		l.messages[code] = map[string]*template.Template{}
+	}
+
+	l.messages[code][normalizeLocale(locale)] = tmpl
+
+	return nil
+}
+
+// AddMessages registers the translations of the locale keyed by code,
+// reporting every invalid template.
+func (l *Localizer) AddMessages(locale string, messages map[string]string) error {
+	errs := []error{}
+
+	for code, message := range messages {
+		if err := l.Add(locale, code, message); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Message returns the message of err in the first translated of the given
+// locales, or of the default locale. The outermost poco.Error of the chain
+// with a translation is used; without any, the message of the outermost
+// poco.Error, or of err itself, is returned.
+func (l *Localizer) Message(err error, locales ...string) string {
+	if err == nil {
+		return ""
+	}
+
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	var (
+		message  string
+		fallback string
+		found    bool
+	)
+
+	walkError(err, func(e error) bool {
+		pocoErr, ok := e.(Error)
+		if !ok {
+			return true
+		}
+
+		if fallback == "" {
+			fallback = pocoErr.message
+		}
+
+		message, found = l.translate(pocoErr, locales)
+
+		return !found
+	})
+
+	switch {
+	case found:
+		return message
+	case fallback != "":
+		return fallback
+	default:
+		return err.Error()
+	}
+}
+
+// Localize returns the message of err in the locales carried by ctx.
+func (l *Localizer) Localize(ctx context.Context, err error) string {
+	return l.Message(err, LocaleFrom(ctx)...)
+}
+
+func (l *Localizer) translate(e Error, locales []string) (string, bool) {
+	translations := l.messages[e.code]
+
+	if len(translations) == 0 {
+		return "", false
+	}
+
+	candidates := []string{}
+
+	for _, locale := range locales {
+		locale = normalizeLocale(locale)
+		candidates = append(candidates, locale)
+
+		if base, _, ok := strings.Cut(locale, "-"); ok {
+			candidates = append(candidates, base)
+		}
+	}
+
+	candidates = append(candidates, l.defaultLocale)
+
+	for _, locale := range candidates {
+		tmpl, ok := translations[locale]
+		if !ok {
+			continue
+		}
+
+		var b strings.Builder
+
+		if err := tmpl.Execute(&b, e.data); err != nil {
+			continue
+		}
+
+		return b.String(), true
+	}
+
+	return "", false
+}
+
+func normalizeLocale(locale string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(locale), "_", "-"))
+}
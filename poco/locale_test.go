@@ -0,0 +1,95 @@
+package poco_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/Arsfiqball/talker/poco"
+)
+
+func TestParseAcceptLanguage(t *testing.T) {
+	t.Run("should order locales by quality", func(t *testing.T) {
+		locales := poco.ParseAcceptLanguage("fr;q=0.5, id-ID, en;q=0.8, *;q=0.1, de;q=0, xx;q=bad")
+		expected := []string{"id-ID", "en", "fr"}
+
+		if !reflect.DeepEqual(locales, expected) {
+			t.Errorf("locales should be %v, got %v", expected, locales)
+		}
+	})
+
+	t.Run("should handle empty header", func(t *testing.T) {
+		if locales := poco.ParseAcceptLanguage(""); len(locales) != 0 {
+			t.Errorf("locales should be empty, got %v", locales)
+		}
+	})
+}
+
+func TestLocalizer(t *testing.T) {
+	errNotFound := poco.NewError("USER_NOT_FOUND", "user not found")
+	errUntranslated := poco.NewError("UNTRANSLATED", "untranslated")
+
+	localizer := poco.NewLocalizer("en")
+
+	err := localizer.AddMessages("en", map[string]string{"USER_NOT_FOUND": "User {{.ID}} was not found"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = localizer.AddMessages("id", map[string]string{"USER_NOT_FOUND": "Pengguna {{.ID}} tidak ditemukan"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := localizer.Add("fr", "USER_NOT_FOUND", "{{.ID"); err == nil {
+		t.Error("invalid template should be reported")
+	}
+
+	data := struct{ ID string }{ID: "42"}
+
+	t.Run("should translate with data", func(t *testing.T) {
+		message := localizer.Message(errNotFound.SetData(data), "id-ID", "en")
+
+		if message != "Pengguna 42 tidak ditemukan" {
+			t.Errorf("unexpected message %q", message)
+		}
+	})
+
+	t.Run("should use the locales of ctx", func(t *testing.T) {
+		ctx := poco.WithLocale(context.Background(), poco.ParseAcceptLanguage("id;q=0.9, de")...)
+		message := localizer.Localize(ctx, fmt.Errorf("handler: %w", errNotFound.SetData(data)))
+
+		if message != "Pengguna 42 tidak ditemukan" {
+			t.Errorf("unexpected message %q", message)
+		}
+	})
+
+	t.Run("should fall back to the default locale", func(t *testing.T) {
+		message := localizer.Message(errNotFound.SetData(map[string]any{"ID": "7"}), "de")
+
+		if message != "User 7 was not found" {
+			t.Errorf("unexpected message %q", message)
+		}
+	})
+
+	t.Run("should fall back to the default message", func(t *testing.T) {
+		cases := []struct {
+			err      error
+			expected string
+		}{
+			{errUntranslated, "untranslated"},
+			{errNotFound, "user not found"}, // missing data
+			{errUntranslated.Wrap(errNotFound.SetData(data)), "User 42 was not found"},
+			{errors.New("plain"), "plain"},
+			{nil, ""},
+		}
+
+		for _, c := range cases {
+			if message := localizer.Message(c.err, "de"); message != c.expected {
+				t.Errorf("message should be %q, got %q", c.expected, message)
+			}
+		}
+	})
+}
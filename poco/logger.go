@@ -0,0 +1,143 @@
+package poco
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// SlogListener logs spans, events and errors through log/slog. It implements
+// SpanListener, EventListener and ErrorListener. Lines carry the trace and
+// span IDs of the span context generated by the Observer.
+type SlogListener struct {
+	Logger     *slog.Logger
+	SpanLevel  slog.Level
+	EventLevel slog.Level
+	ErrorLevel slog.Level
+}
+
+// NewSlogListener returns a SlogListener logging spans at debug level, events
+// at info level and errors at error level. A nil logger means slog.Default().
+func NewSlogListener(logger *slog.Logger) *SlogListener {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &SlogListener{
+		Logger:     logger,
+		SpanLevel:  slog.LevelDebug,
+		EventLevel: slog.LevelInfo,
+		ErrorLevel: slog.LevelError,
+	}
+}
+
+func (l *SlogListener) ids(ctx context.Context) []any {
+	sc, ok := SpanContextFrom(ctx)
+
+	if !ok {
+		return nil
+	}
+
+	return []any{slog.String("trace_id", sc.TraceID), slog.String("span_id", sc.SpanID)}
+}
+
+func (l *SlogListener) OnSpan(ctx context.Context, name string, attrs []any) (context.Context, SpanEnd) {
+	ctx, recorder := l.OnSpanStart(ctx, name, attrs)
+
+	return ctx, recorder.End
+}
+
+func (l *SlogListener) OnSpanStart(ctx context.Context, name string, attrs []any) (context.Context, SpanRecorder) {
+	l.Logger.Log(ctx, l.SpanLevel, "span start", append(append([]any{slog.String("span", name)}, l.ids(ctx)...), attrs...)...)
+
+	return ctx, &slogSpanRecorder{listener: l, ctx: ctx, name: name, start: time.Now()}
+}
+
+func (l *SlogListener) OnEvent(ctx context.Context, name string, attrs []any) {
+	l.Logger.Log(ctx, l.EventLevel, name, append(l.ids(ctx), attrs...)...)
+}
+
+func (l *SlogListener) OnError(ctx context.Context, err error) error {
+	l.Logger.Log(ctx, l.ErrorLevel, err.Error(), append(l.ids(ctx), ErrorAttr(err))...)
+
+	return err
+}
+
+// ErrorAttr renders err as a slog group holding its message and, for a poco
+// Error, its code, data and trace.
+func ErrorAttr(err error) slog.Attr {
+	attrs := []any{slog.String("message", err.Error())}
+
+	var pocoErr Error
+
+	if errors.As(err, &pocoErr) {
+		attrs = append(attrs, slog.String("code", pocoErr.code))
+
+		if pocoErr.data != nil {
+			attrs = append(attrs, slog.Any("data", pocoErr.data))
+		}
+
+		attrs = append(attrs, slog.Any("trace", TraceError(err)))
+	}
+
+	return slog.Group("error", attrs...)
+}
+
+type slogSpanRecorder struct {
+	mu          sync.Mutex
+	listener    *SlogListener
+	ctx         context.Context
+	name        string
+	start       time.Time
+	attrs       []any
+	status      SpanStatus
+	description string
+}
+
+func (r *slogSpanRecorder) SetAttributes(attrs []any) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.attrs = append(r.attrs, attrs...)
+}
+
+func (r *slogSpanRecorder) AddEvent(name string, attrs []any) {
+	r.listener.OnEvent(r.ctx, name, attrs)
+}
+
+func (r *slogSpanRecorder) RecordError(err error) {
+	_ = r.listener.OnError(r.ctx, err)
+}
+
+func (r *slogSpanRecorder) SetStatus(status SpanStatus, description string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.status = status
+	r.description = description
+}
+
+func (r *slogSpanRecorder) End() {
+	r.EndAt(time.Now())
+}
+
+func (r *slogSpanRecorder) EndAt(t time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	args := []any{slog.String("span", r.name), slog.Duration("duration", t.Sub(r.start))}
+
+	if r.status != SpanStatusUnset {
+		args = append(args, slog.String("status", r.status.String()))
+	}
+
+	if r.description != "" {
+		args = append(args, slog.String("status_description", r.description))
+	}
+
+	args = append(append(args, r.listener.ids(r.ctx)...), r.attrs...)
+
+	r.listener.Logger.Log(r.ctx, r.listener.SpanLevel, "span end", args...)
+}
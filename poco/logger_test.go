@@ -0,0 +1,112 @@
+package poco_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/Arsfiqball/talker/poco"
+)
+
+func decodeLogLines(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+
+	lines := []map[string]any{}
+
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var m map[string]any
+
+		if err := json.Unmarshal([]byte(line), &m); err != nil {
+			t.Fatal(err)
+		}
+
+		lines = append(lines, m)
+	}
+
+	return lines
+}
+
+func TestSlogListener(t *testing.T) {
+	t.Run("log span, event and error", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+		obs := poco.NewObserver(poco.WithListener(poco.NewSlogListener(logger)))
+
+		errNotFound := poco.NewError("NOT_FOUND", "user not found").SetData(map[string]any{"id": 42})
+
+		ctx, end := obs.Span(context.Background(), "find user", []any{"id", 42})
+		obs.Event(ctx, "cache miss", []any{"key", "user:42"})
+		_ = obs.Error(ctx, errNotFound)
+		end()
+
+		lines := decodeLogLines(t, &buf)
+
+		if len(lines) != 4 {
+			t.Fatalf("expected 4 lines, got %d", len(lines))
+		}
+
+		start, event, errLine, stop := lines[0], lines[1], lines[2], lines[3]
+
+		if start["msg"] != "span start" || start["span"] != "find user" || start["id"] != float64(42) {
+			t.Errorf("unexpected span start %v", start)
+		}
+
+		if stop["msg"] != "span end" || stop["duration"] == nil {
+			t.Errorf("unexpected span end %v", stop)
+		}
+
+		if event["msg"] != "cache miss" || event["key"] != "user:42" {
+			t.Errorf("unexpected event %v", event)
+		}
+
+		for _, line := range lines {
+			if line["trace_id"] != start["trace_id"] || line["span_id"] != start["span_id"] {
+				t.Errorf("line should carry the span ids, got %v", line)
+			}
+		}
+
+		if errLine["level"] != "ERROR" || errLine["msg"] != "user not found" {
+			t.Errorf("unexpected error line %v", errLine)
+		}
+
+		errGroup, _ := errLine["error"].(map[string]any)
+
+		if errGroup["code"] != "NOT_FOUND" {
+			t.Errorf("error code should be NOT_FOUND, got %v", errGroup["code"])
+		}
+
+		if data, _ := errGroup["data"].(map[string]any); data["id"] != float64(42) {
+			t.Errorf("error data should carry id, got %v", errGroup["data"])
+		}
+
+		if trace, _ := errGroup["trace"].([]any); len(trace) != 1 {
+			t.Errorf("error trace should have 1 entry, got %v", errGroup["trace"])
+		}
+	})
+
+	t.Run("child span shares trace id", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+		obs := poco.NewObserver(poco.WithListener(poco.NewSlogListener(logger)))
+
+		ctx, end := obs.Span(context.Background(), "parent", nil)
+		_, endChild := obs.Span(ctx, "child", nil)
+		endChild()
+		end()
+
+		lines := decodeLogLines(t, &buf)
+
+		if lines[0]["trace_id"] != lines[1]["trace_id"] {
+			t.Error("child should share the trace id of parent")
+		}
+
+		if lines[0]["span_id"] == lines[1]["span_id"] {
+			t.Error("child should have its own span id")
+		}
+	})
+}
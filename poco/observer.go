@@ -1,6 +1,9 @@
 package poco
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 type SpanEnd func()
 
@@ -8,6 +11,151 @@ type SpanListener interface {
 	OnSpan(ctx context.Context, name string, attrs []any) (context.Context, SpanEnd)
 }
 
+type SpanStatus int
+
+const (
+	SpanStatusUnset SpanStatus = iota
+	SpanStatusOK
+	SpanStatusError
+)
+
+func (s SpanStatus) String() string {
+	switch s {
+	case SpanStatusOK:
+		return "ok"
+	case SpanStatusError:
+		return "error"
+	}
+
+	return "unset"
+}
+
+// SpanRecorder receives what happens during a span started by a SpanHandleListener.
+type SpanRecorder interface {
+	SetAttributes(attrs []any)
+	AddEvent(name string, attrs []any)
+	RecordError(err error)
+	SetStatus(status SpanStatus, description string)
+	End()
+}
+
+// SpanEndAtRecorder is a SpanRecorder which can end the span at a given time.
+// AsyncListener delivers End later than it was called, and ends the span with
+// the time of the call when the recorder implements it.
+type SpanEndAtRecorder interface {
+	SpanRecorder
+	EndAt(t time.Time)
+}
+
+// SpanHandleListener is a SpanListener which also receives attributes, events,
+// errors and status set on the span while it runs.
+type SpanHandleListener interface {
+	OnSpanStart(ctx context.Context, name string, attrs []any) (context.Context, SpanRecorder)
+}
+
+// legacySpanRecorder adapts a SpanListener to SpanHandleListener. Events and
+// errors are forwarded to the listener when it is also an EventListener or an
+// ErrorListener, other calls are dropped.
+type legacySpanRecorder struct {
+	ctx      context.Context
+	listener SpanListener
+	end      SpanEnd
+}
+
+type legacySpanListener struct {
+	listener SpanListener
+}
+
+func (l legacySpanListener) OnSpanStart(ctx context.Context, name string, attrs []any) (context.Context, SpanRecorder) {
+	ctx, end := l.listener.OnSpan(ctx, name, attrs)
+
+	return ctx, &legacySpanRecorder{ctx: ctx, listener: l.listener, end: end}
+}
+
+func (r *legacySpanRecorder) SetAttributes(attrs []any) {}
+
+func (r *legacySpanRecorder) AddEvent(name string, attrs []any) {
+	if eventListener, ok := r.listener.(EventListener); ok {
+		eventListener.OnEvent(r.ctx, name, attrs)
+	}
+}
+
+func (r *legacySpanRecorder) RecordError(err error) {
+	if errorListener, ok := r.listener.(ErrorListener); ok {
+		_ = errorListener.OnError(r.ctx, err)
+	}
+}
+
+func (r *legacySpanRecorder) SetStatus(status SpanStatus, description string) {}
+
+func (r *legacySpanRecorder) End() {
+	if r.end != nil {
+		r.end()
+	}
+}
+
+// SpanHandle is a running span. All methods are safe to call on a nil handle.
+type SpanHandle struct {
+	recorders []SpanRecorder
+	observer  *Observer
+	ctx       context.Context
+	name      string
+}
+
+func (s *SpanHandle) SetAttributes(attrs []any) {
+	if s == nil {
+		return
+	}
+
+	s.observer.checkAttrs(s.ctx, "span "+s.name, attrs)
+
+	for _, recorder := range s.recorders {
+		recorder.SetAttributes(attrs)
+	}
+}
+
+func (s *SpanHandle) AddEvent(name string, attrs []any) {
+	if s == nil {
+		return
+	}
+
+	s.observer.checkAttrs(s.ctx, "event "+name, attrs)
+
+	for _, recorder := range s.recorders {
+		recorder.AddEvent(name, attrs)
+	}
+}
+
+func (s *SpanHandle) RecordError(err error) {
+	if s == nil || err == nil {
+		return
+	}
+
+	for _, recorder := range s.recorders {
+		recorder.RecordError(err)
+	}
+}
+
+func (s *SpanHandle) SetStatus(status SpanStatus, description string) {
+	if s == nil {
+		return
+	}
+
+	for _, recorder := range s.recorders {
+		recorder.SetStatus(status, description)
+	}
+}
+
+func (s *SpanHandle) End() {
+	if s == nil {
+		return
+	}
+
+	for _, recorder := range s.recorders {
+		recorder.End()
+	}
+}
+
 type EventListener interface {
 	OnEvent(ctx context.Context, name string, attrs []any)
 }
@@ -16,18 +164,26 @@ type ErrorListener interface {
 	OnError(ctx context.Context, err error) error
 }
 
+// Observer dispatches spans, events and errors to its listeners. All methods
+// are safe to call on a nil observer, which does nothing.
 type Observer struct {
-	spanListeners  []SpanListener
+	spanListeners  []SpanHandleListener
 	eventListeners []EventListener
 	errorListeners []ErrorListener
+	sampler        Sampler
+	spanFilters    []Filter
+	eventFilters   []Filter
+	attrReporter   func(ctx context.Context, err error)
 }
 
 type ObserverOption func(*Observer) error
 
 func WithListener(listener interface{}) ObserverOption {
 	return func(o *Observer) error {
-		if startListener, ok := listener.(SpanListener); ok {
-			o.spanListeners = append(o.spanListeners, startListener)
+		if handleListener, ok := listener.(SpanHandleListener); ok {
+			o.spanListeners = append(o.spanListeners, handleListener)
+		} else if startListener, ok := listener.(SpanListener); ok {
+			o.spanListeners = append(o.spanListeners, legacySpanListener{listener: startListener})
 		}
 
 		if eventListener, ok := listener.(EventListener); ok {
@@ -42,6 +198,31 @@ func WithListener(listener interface{}) ObserverOption {
 	}
 }
 
+// WithAttrReporter validates the attributes of spans and events and calls
+// report with an error matching ErrMalformedAttrs for malformed ones, see
+// ParseAttrs. Attributes are not validated by default, and malformed ones are
+// still handed to the listeners.
+func WithAttrReporter(report func(ctx context.Context, err error)) ObserverOption {
+	return func(o *Observer) error {
+		o.attrReporter = report
+		return nil
+	}
+}
+
+func (o *Observer) checkAttrs(ctx context.Context, subject string, attrs []any) {
+	if o.attrReporter == nil || validAttrs(attrs) {
+		return
+	}
+
+	_, err := ParseAttrs(attrs)
+
+	if pocoErr, ok := err.(Error); ok {
+		err = pocoErr.SetInfo(subject + ": " + pocoErr.message)
+	}
+
+	o.attrReporter(ctx, err)
+}
+
 func NewObserver(opts ...ObserverOption) *Observer {
 	o := &Observer{}
 
@@ -53,31 +234,80 @@ func NewObserver(opts ...ObserverOption) *Observer {
 }
 
 func (o *Observer) Span(ctx context.Context, name string, attrs []any) (context.Context, SpanEnd) {
-	ends := []SpanEnd{}
+	ctx, span := o.StartSpan(ctx, name, attrs)
+
+	return ctx, span.End
+}
+
+// StartSpan starts a span named name with its listeners. The span gets a new
+// span context in the trace of the span context of ctx. A listener creating its
+// own IDs, as an OpenTelemetry tracer does, can replace it by returning a ctx
+// carrying another span context, and finds the span context of the parent with
+// ParentSpanContextFrom.
+func (o *Observer) StartSpan(ctx context.Context, name string, attrs []any) (context.Context, *SpanHandle) {
+	if o == nil {
+		return ctx, nil
+	}
+
+	o.checkAttrs(ctx, "span "+name, attrs)
+
+	if !keep(o.spanFilters, name, attrs) {
+		return ctx, nil
+	}
+
+	sampled := true
+
+	if o.sampler != nil {
+		sampled = o.sampler.ShouldSample(ctx, name, attrs)
+		ctx = context.WithValue(ctx, sampledKey{}, sampled)
+	}
+
+	parent, _ := SpanContextFrom(ctx)
+	ctx = WithSpanContext(ctx, childSpanContext(ctx, sampled))
+
+	if !sampled {
+		return ctx, nil
+	}
+
+	ctx = context.WithValue(ctx, parentSpanContextKey{}, parent)
+
+	span := &SpanHandle{observer: o, name: name}
 
 	for _, listener := range o.spanListeners {
-		newCtx, end := listener.OnSpan(ctx, name, attrs)
+		newCtx, recorder := listener.OnSpanStart(ctx, name, attrs)
 
-		ends = append(ends, end)
+		span.recorders = append(span.recorders, recorder)
 		ctx = newCtx
 	}
 
-	return ctx, func() {
-		for _, end := range ends {
-			end()
-		}
-	}
+	span.ctx = ctx
+
+	return ctx, span
 }
 
 func (o *Observer) Event(ctx context.Context, name string, attrs []any) {
+	if o == nil {
+		return
+	}
+
+	o.checkAttrs(ctx, "event "+name, attrs)
+
+	if sampled, ok := Sampled(ctx); ok && !sampled {
+		return
+	}
+
+	if !keep(o.eventFilters, name, attrs) {
+		return
+	}
+
 	for _, listener := range o.eventListeners {
 		listener.OnEvent(ctx, name, attrs)
 	}
 }
 
 func (o *Observer) Error(ctx context.Context, err error) error {
-	if err == nil {
-		return nil
+	if o == nil || err == nil {
+		return err
 	}
 
 	for _, listener := range o.errorListeners {
@@ -1,9 +1,134 @@
 package poco
 
 import (
+	"context"
+	"errors"
 	"testing"
 )
 
+type legacyListener struct {
+	spans  []string
+	ended  int
+	events []string
+	errs   []error
+}
+
+func (l *legacyListener) OnSpan(ctx context.Context, name string, attrs []any) (context.Context, SpanEnd) {
+	l.spans = append(l.spans, name)
+
+	return ctx, func() {
+		l.ended++
+	}
+}
+
+func (l *legacyListener) OnEvent(ctx context.Context, name string, attrs []any) {
+	l.events = append(l.events, name)
+}
+
+func (l *legacyListener) OnError(ctx context.Context, err error) error {
+	l.errs = append(l.errs, err)
+
+	return err
+}
+
+type handleListener struct {
+	attrs  []any
+	status SpanStatus
+	errs   []error
+	ended  bool
+}
+
+func (l *handleListener) OnSpanStart(ctx context.Context, name string, attrs []any) (context.Context, SpanRecorder) {
+	l.attrs = append(l.attrs, attrs...)
+
+	return ctx, l
+}
+
+func (l *handleListener) SetAttributes(attrs []any) {
+	l.attrs = append(l.attrs, attrs...)
+}
+
+func (l *handleListener) AddEvent(name string, attrs []any) {}
+
+func (l *handleListener) RecordError(err error) {
+	l.errs = append(l.errs, err)
+}
+
+func (l *handleListener) SetStatus(status SpanStatus, description string) {
+	l.status = status
+}
+
+func (l *handleListener) End() {
+	l.ended = true
+}
+
 func TestObserver(t *testing.T) {
-	// TODO: implement
+	t.Run("span handle forwarded to listeners", func(t *testing.T) {
+		legacy := &legacyListener{}
+		handle := &handleListener{}
+		obs := NewObserver(WithListener(legacy), WithListener(handle))
+
+		errTest := errors.New("test")
+
+		_, span := obs.StartSpan(context.Background(), "test", []any{"a", 1})
+		span.SetAttributes([]any{"b", 2})
+		span.AddEvent("happened", nil)
+		span.RecordError(errTest)
+		span.SetStatus(SpanStatusError, "test")
+		span.End()
+
+		if len(legacy.spans) != 1 || legacy.ended != 1 {
+			t.Errorf("legacy listener should see one started and ended span, got %v and %d", legacy.spans, legacy.ended)
+		}
+
+		if len(legacy.events) != 1 || len(legacy.errs) != 1 {
+			t.Errorf("legacy listener should receive events and errors, got %v and %v", legacy.events, legacy.errs)
+		}
+
+		if len(handle.attrs) != 4 {
+			t.Errorf("handle listener should receive 4 attributes, got %v", handle.attrs)
+		}
+
+		if handle.status != SpanStatusError || len(handle.errs) != 1 || !handle.ended {
+			t.Errorf("handle listener should receive status, error and end, got %+v", handle)
+		}
+	})
+
+	t.Run("legacy span end", func(t *testing.T) {
+		legacy := &legacyListener{}
+		obs := NewObserver(WithListener(legacy))
+
+		_, end := obs.Span(context.Background(), "test", nil)
+		end()
+
+		if legacy.ended != 1 {
+			t.Errorf("span should be ended once, got %d", legacy.ended)
+		}
+	})
+
+	t.Run("nil span handle", func(t *testing.T) {
+		var span *SpanHandle
+
+		span.SetAttributes([]any{"a", 1})
+		span.RecordError(errors.New("test"))
+		span.End()
+	})
+
+	t.Run("nil observer", func(t *testing.T) {
+		var obs *Observer
+
+		ctx, span := obs.StartSpan(context.Background(), "test", nil)
+		span.End()
+
+		_, end := obs.Span(ctx, "test", nil)
+		end()
+
+		obs.Event(ctx, "test", nil)
+
+		errTest := errors.New("test")
+
+		if err := obs.Error(ctx, errTest); err != errTest {
+			t.Errorf("error should be returned as is, got %v", err)
+		}
+	})
 }
@@ -0,0 +1,257 @@
+// Package otel adapts poco.Observer listeners to OpenTelemetry tracing.
+package otel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/Arsfiqball/talker/poco"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Listener maps poco spans, events and errors to OpenTelemetry. It implements
+// poco.SpanListener, poco.EventListener and poco.ErrorListener.
+type Listener struct {
+	tracer trace.Tracer
+}
+
+// NewListener returns a Listener creating spans with tracer.
+func NewListener(tracer trace.Tracer) *Listener {
+	return &Listener{tracer: tracer}
+}
+
+// OnSpan starts an OpenTelemetry span.
+func (l *Listener) OnSpan(ctx context.Context, name string, attrs []any) (context.Context, poco.SpanEnd) {
+	ctx, recorder := l.OnSpanStart(ctx, name, attrs)
+
+	return ctx, recorder.End
+}
+
+// OnSpanStart starts an OpenTelemetry span which receives the attributes,
+// events, errors and status set on the poco span. The parent span context of
+// the poco span, received with a traceparent header for instance, becomes the
+// parent of the OpenTelemetry span, and the poco span adopts its IDs, so logs
+// and propagated headers match the exported traces. The listener must hence
+// come before the listeners logging span IDs, such as poco.SlogListener.
+func (l *Listener) OnSpanStart(ctx context.Context, name string, attrs []any) (context.Context, poco.SpanRecorder) {
+	if parent, ok := poco.ParentSpanContextFrom(ctx); ok && trace.SpanContextFromContext(ctx).SpanID().String() != parent.SpanID {
+		if sc, ok := otelSpanContext(parent); ok {
+			ctx = trace.ContextWithRemoteSpanContext(ctx, sc)
+		}
+	}
+
+	ctx, span := l.tracer.Start(ctx, name, trace.WithAttributes(Attributes(attrs)...))
+
+	if sc := span.SpanContext(); sc.IsValid() {
+		ctx = poco.WithSpanContext(ctx, poco.SpanContext{
+			TraceID:    sc.TraceID().String(),
+			SpanID:     sc.SpanID().String(),
+			Sampled:    sc.IsSampled(),
+			TraceState: sc.TraceState().String(),
+		})
+	}
+
+	return ctx, spanRecorder{span: span}
+}
+
+// otelSpanContext converts a remote poco span context.
+func otelSpanContext(sc poco.SpanContext) (trace.SpanContext, bool) {
+	traceID, err := trace.TraceIDFromHex(sc.TraceID)
+	if err != nil {
+		return trace.SpanContext{}, false
+	}
+
+	spanID, err := trace.SpanIDFromHex(sc.SpanID)
+	if err != nil {
+		return trace.SpanContext{}, false
+	}
+
+	config := trace.SpanContextConfig{TraceID: traceID, SpanID: spanID, Remote: true}
+
+	if sc.Sampled {
+		config.TraceFlags = trace.FlagsSampled
+	}
+
+	if state, err := trace.ParseTraceState(sc.TraceState); err == nil {
+		config.TraceState = state
+	}
+
+	return trace.NewSpanContext(config), true
+}
+
+// OnEvent adds an event to the span in ctx.
+func (l *Listener) OnEvent(ctx context.Context, name string, attrs []any) {
+	trace.SpanFromContext(ctx).AddEvent(name, trace.WithAttributes(Attributes(attrs)...))
+}
+
+// OnError records err as an exception of the span in ctx and marks the span as failed.
+func (l *Listener) OnError(ctx context.Context, err error) error {
+	recordError(trace.SpanFromContext(ctx), err)
+
+	return err
+}
+
+func recordError(span trace.Span, err error) {
+	if !span.IsRecording() {
+		return
+	}
+
+	attrs := []attribute.KeyValue{}
+
+	var pocoErr poco.Error
+
+	if errors.As(err, &pocoErr) {
+		attrs = append(attrs,
+			attribute.String("error.code", pocoErr.Code()),
+			attribute.String("error.trace", strings.Join(poco.TraceError(err), "\n")),
+		)
+	}
+
+	span.RecordError(err, trace.WithAttributes(attrs...))
+	span.SetStatus(codes.Error, err.Error())
+}
+
+type spanRecorder struct {
+	span trace.Span
+}
+
+func (r spanRecorder) SetAttributes(attrs []any) {
+	r.span.SetAttributes(Attributes(attrs)...)
+}
+
+func (r spanRecorder) AddEvent(name string, attrs []any) {
+	r.span.AddEvent(name, trace.WithAttributes(Attributes(attrs)...))
+}
+
+func (r spanRecorder) RecordError(err error) {
+	recordError(r.span, err)
+}
+
+func (r spanRecorder) SetStatus(status poco.SpanStatus, description string) {
+	switch status {
+	case poco.SpanStatusOK:
+		r.span.SetStatus(codes.Ok, "")
+	case poco.SpanStatusError:
+		r.span.SetStatus(codes.Error, description)
+	default:
+		r.span.SetStatus(codes.Unset, "")
+	}
+}
+
+func (r spanRecorder) End() {
+	r.span.End()
+}
+
+func (r spanRecorder) EndAt(t time.Time) {
+	r.span.End(trace.WithTimestamp(t))
+}
+
+// Attributes converts attrs, made of poco.Attr values and alternating
+// key/value pairs, into typed attributes. attribute.KeyValue items are accepted
+// too. Groups are flattened into dotted keys. Malformed pairs are reported under
+// the "!BADKEY" key, see poco.ParseAttrs.
+func Attributes(attrs []any) []attribute.KeyValue {
+	normalized := make([]any, len(attrs))
+
+	for i, attr := range attrs {
+		if kv, ok := attr.(attribute.KeyValue); ok {
+			attr = poco.Any(string(kv.Key), kv.Value.AsInterface())
+		}
+
+		normalized[i] = attr
+	}
+
+	parsed, _ := poco.ParseAttrs(normalized)
+	result := make([]attribute.KeyValue, 0, len(parsed))
+
+	for _, attr := range parsed {
+		result = appendAttr(result, "", attr)
+	}
+
+	return result
+}
+
+func appendAttr(result []attribute.KeyValue, prefix string, attr poco.Attr) []attribute.KeyValue {
+	key := prefix + attr.Key
+	value := attr.Value.Resolve()
+
+	switch value.Kind() {
+	case slog.KindGroup:
+		for _, child := range value.Group() {
+			result = appendAttr(result, key+".", child)
+		}
+
+		return result
+	case slog.KindString:
+		return append(result, attribute.String(key, value.String()))
+	case slog.KindInt64:
+		return append(result, attribute.Int64(key, value.Int64()))
+	case slog.KindUint64:
+		return append(result, attribute.Int64(key, int64(value.Uint64())))
+	case slog.KindFloat64:
+		return append(result, attribute.Float64(key, value.Float64()))
+	case slog.KindBool:
+		return append(result, attribute.Bool(key, value.Bool()))
+	case slog.KindDuration:
+		return append(result, attribute.String(key, value.Duration().String()))
+	case slog.KindTime:
+		return append(result, attribute.String(key, value.Time().Format(time.RFC3339Nano)))
+	}
+
+	return append(result, Attribute(key, value.Any()))
+}
+
+// Attribute converts a value into a typed attribute. Values of unsupported
+// types are formatted as strings.
+func Attribute(key string, value any) attribute.KeyValue {
+	switch v := value.(type) {
+	case string:
+		return attribute.String(key, v)
+	case bool:
+		return attribute.Bool(key, v)
+	case int:
+		return attribute.Int(key, v)
+	case int8:
+		return attribute.Int(key, int(v))
+	case int16:
+		return attribute.Int(key, int(v))
+	case int32:
+		return attribute.Int(key, int(v))
+	case int64:
+		return attribute.Int64(key, v)
+	case uint8:
+		return attribute.Int(key, int(v))
+	case uint16:
+		return attribute.Int(key, int(v))
+	case uint32:
+		return attribute.Int64(key, int64(v))
+	case float32:
+		return attribute.Float64(key, float64(v))
+	case float64:
+		return attribute.Float64(key, v)
+	case time.Duration:
+		return attribute.String(key, v.String())
+	case []string:
+		return attribute.StringSlice(key, v)
+	case []bool:
+		return attribute.BoolSlice(key, v)
+	case []int:
+		return attribute.IntSlice(key, v)
+	case []int64:
+		return attribute.Int64Slice(key, v)
+	case []float64:
+		return attribute.Float64Slice(key, v)
+	case fmt.Stringer:
+		return attribute.String(key, v.String())
+	case error:
+		return attribute.String(key, v.Error())
+	}
+
+	return attribute.String(key, fmt.Sprintf("%+v", value))
+}
@@ -0,0 +1,237 @@
+package otel_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Arsfiqball/talker/poco"
+	pocotel "github.com/Arsfiqball/talker/poco/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func newObserver() (*poco.Observer, *tracetest.InMemoryExporter) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	listener := pocotel.NewListener(provider.Tracer("test"))
+
+	return poco.NewObserver(poco.WithListener(listener)), exporter
+}
+
+func attrValue(attrs []attribute.KeyValue, key string) (attribute.Value, bool) {
+	for _, kv := range attrs {
+		if string(kv.Key) == key {
+			return kv.Value, true
+		}
+	}
+
+	return attribute.Value{}, false
+}
+
+func TestListener(t *testing.T) {
+	t.Run("span with attributes and event", func(t *testing.T) {
+		obs, exporter := newObserver()
+
+		ctx, end := obs.Span(context.Background(), "parent", []any{"user", "alice", "count", 3})
+		obs.Event(ctx, "something happened", []any{"ok", true})
+
+		_, endChild := obs.Span(ctx, "child", nil)
+		endChild()
+		end()
+
+		spans := exporter.GetSpans()
+
+		if len(spans) != 2 {
+			t.Fatalf("expected 2 spans, got %d", len(spans))
+		}
+
+		child, parent := spans[0], spans[1]
+
+		if parent.Name != "parent" || child.Name != "child" {
+			t.Fatalf("unexpected span names %s, %s", parent.Name, child.Name)
+		}
+
+		if child.Parent.SpanID() != parent.SpanContext.SpanID() {
+			t.Error("child should be a child of parent")
+		}
+
+		if v, ok := attrValue(parent.Attributes, "user"); !ok || v.AsString() != "alice" {
+			t.Errorf("user attribute should be alice, got %v", v.Emit())
+		}
+
+		if v, ok := attrValue(parent.Attributes, "count"); !ok || v.AsInt64() != 3 {
+			t.Errorf("count attribute should be 3, got %v", v.Emit())
+		}
+
+		if len(parent.Events) != 1 || parent.Events[0].Name != "something happened" {
+			t.Fatalf("parent should have one event, got %v", parent.Events)
+		}
+
+		if v, ok := attrValue(parent.Events[0].Attributes, "ok"); !ok || !v.AsBool() {
+			t.Errorf("ok attribute should be true, got %v", v.Emit())
+		}
+	})
+
+	t.Run("error recorded as exception", func(t *testing.T) {
+		obs, exporter := newObserver()
+
+		errNotFound := poco.NewError("NOT_FOUND", "not found")
+
+		ctx, end := obs.Span(context.Background(), "find", nil)
+		err := obs.Error(ctx, errNotFound.Wrap(context.Canceled))
+		end()
+
+		if err == nil {
+			t.Fatal("error should be returned")
+		}
+
+		span := exporter.GetSpans()[0]
+
+		if span.Status.Code != codes.Error || span.Status.Description != "not found" {
+			t.Errorf("unexpected status %v", span.Status)
+		}
+
+		if len(span.Events) != 1 || span.Events[0].Name != "exception" {
+			t.Fatalf("span should have one exception event, got %v", span.Events)
+		}
+
+		if v, ok := attrValue(span.Events[0].Attributes, "error.code"); !ok || v.AsString() != "NOT_FOUND" {
+			t.Errorf("error.code attribute should be NOT_FOUND, got %v", v.Emit())
+		}
+	})
+
+	t.Run("async span keeps its end time", func(t *testing.T) {
+		exporter := tracetest.NewInMemoryExporter()
+		provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+		async := poco.NewAsyncListener(pocotel.NewListener(provider.Tracer("test")), poco.WithAsyncFlushInterval(time.Hour))
+		obs := poco.NewObserver(poco.WithListener(async))
+
+		_, end := obs.Span(context.Background(), "span", nil)
+		end()
+
+		time.Sleep(50 * time.Millisecond)
+
+		_ = async.Shutdown(context.Background())
+
+		spans := exporter.GetSpans()
+
+		if len(spans) != 1 {
+			t.Fatalf("expected 1 span, got %d", len(spans))
+		}
+
+		if d := spans[0].EndTime.Sub(spans[0].StartTime); d >= 50*time.Millisecond {
+			t.Errorf("duration should not include the queueing delay, got %s", d)
+		}
+	})
+}
+
+func TestTraceParent(t *testing.T) {
+	t.Run("should continue the trace of the request", func(t *testing.T) {
+		exporter := tracetest.NewInMemoryExporter()
+		provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+
+		var logs bytes.Buffer
+
+		slogListener := poco.NewSlogListener(slog.New(slog.NewJSONHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug})))
+		obs := poco.NewObserver(poco.WithListener(pocotel.NewListener(provider.Tracer("test"))), poco.WithListener(slogListener))
+
+		var handled poco.SpanContext
+
+		handler := poco.HTTPMiddleware(obs)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			handled, _ = poco.SpanContextFrom(r.Context())
+		}))
+
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+
+		spans := exporter.GetSpans()
+
+		if len(spans) != 1 {
+			t.Fatalf("expected 1 span, got %d", len(spans))
+		}
+
+		span := spans[0]
+
+		if span.SpanContext.TraceID().String() != "4bf92f3577b34da6a3ce929d0e0e4736" || span.Parent.SpanID().String() != "00f067aa0ba902b7" {
+			t.Errorf("span should be a child of the traceparent, got trace %s parent %s", span.SpanContext.TraceID(), span.Parent.SpanID())
+		}
+
+		if handled.TraceID != span.SpanContext.TraceID().String() || handled.SpanID != span.SpanContext.SpanID().String() {
+			t.Errorf("poco span context should match the exported span, got %+v", handled)
+		}
+
+		for _, line := range strings.Split(strings.TrimSpace(logs.String()), "\n") {
+			if !strings.Contains(line, `"span_id":"`+handled.SpanID+`"`) {
+				t.Errorf("log should carry the exported span id, got %s", line)
+			}
+		}
+	})
+}
+
+func TestSpanHandle(t *testing.T) {
+	obs, exporter := newObserver()
+
+	_, span := obs.StartSpan(context.Background(), "query", nil)
+	span.SetAttributes([]any{"rows", 3})
+	span.AddEvent("retry", nil)
+	span.SetStatus(poco.SpanStatusOK, "")
+	span.End()
+
+	got := exporter.GetSpans()[0]
+
+	if v, ok := attrValue(got.Attributes, "rows"); !ok || v.AsInt64() != 3 {
+		t.Errorf("rows attribute should be 3, got %v", v.Emit())
+	}
+
+	if len(got.Events) != 1 || got.Events[0].Name != "retry" {
+		t.Errorf("span should have one retry event, got %v", got.Events)
+	}
+
+	if got.Status.Code != codes.Ok {
+		t.Errorf("status should be ok, got %v", got.Status)
+	}
+}
+
+func TestAttributes(t *testing.T) {
+	attrs := pocotel.Attributes([]any{
+		"name", "x",
+		"duration", time.Second,
+		"ratio", 0.5,
+		attribute.Int("typed", 1),
+		poco.Group("req", poco.Int("status", 200), poco.Bool("cached", true)),
+		42, "key", "value",
+		"dangling",
+	})
+
+	expected := []attribute.KeyValue{
+		attribute.String("name", "x"),
+		attribute.String("duration", "1s"),
+		attribute.Float64("ratio", 0.5),
+		attribute.Int("typed", 1),
+		attribute.Int("req.status", 200),
+		attribute.Bool("req.cached", true),
+		attribute.Int("!BADKEY", 42),
+		attribute.String("key", "value"),
+		attribute.String("!BADKEY", "dangling"),
+	}
+
+	if len(attrs) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, attrs)
+	}
+
+	for i := range expected {
+		if attrs[i] != expected[i] {
+			t.Errorf("attribute %d should be %v, got %v", i, expected[i], attrs[i])
+		}
+	}
+}
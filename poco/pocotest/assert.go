@@ -0,0 +1,195 @@
+package pocotest
+
+import (
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Arsfiqball/talker/poco"
+)
+
+// UpdateEnv is the environment variable which, set to "1", makes AssertGolden
+// write the golden files instead of comparing them.
+const UpdateEnv = "POCOTEST_UPDATE"
+
+// SpanAssert chains assertions on a captured span.
+type SpanAssert struct {
+	t    testing.TB
+	span *Span
+}
+
+// AssertSpan fails the test unless a span named name was captured, and
+// returns assertions on the first one.
+func (r *Recorder) AssertSpan(t testing.TB, name string) SpanAssert {
+	t.Helper()
+
+	span := r.Span(name)
+
+	if span == nil {
+		t.Fatalf("pocotest: span %q not found in\n%s", name, r.Tree())
+	}
+
+	return SpanAssert{t: t, span: span}
+}
+
+// Span returns the asserted span.
+func (a SpanAssert) Span() *Span {
+	return a.span
+}
+
+// HasChild fails the test unless the span has a direct child named name, and
+// returns assertions on it.
+func (a SpanAssert) HasChild(name string) SpanAssert {
+	a.t.Helper()
+
+	child := a.span.Child(name)
+
+	if child == nil {
+		names := []string{}
+
+		for _, c := range a.span.Children {
+			names = append(names, c.Name)
+		}
+
+		a.t.Fatalf("pocotest: span %q has no child %q, children are %q", a.span.Name, name, names)
+	}
+
+	return SpanAssert{t: a.t, span: child}
+}
+
+// HasAttr checks that the span has an attribute named key equal to value, as
+// compared by log/slog.
+func (a SpanAssert) HasAttr(key string, value any) SpanAssert {
+	a.t.Helper()
+
+	actual, ok := a.span.Attr(key)
+
+	switch {
+	case !ok:
+		a.t.Errorf("pocotest: span %q has no attribute %q", a.span.Name, key)
+	case !slog.AnyValue(actual).Equal(slog.AnyValue(value)):
+		a.t.Errorf("pocotest: span %q attribute %q should be %v, got %v", a.span.Name, key, value, actual)
+	}
+
+	return a
+}
+
+// HasEvent checks that an event named name was captured in the span.
+func (a SpanAssert) HasEvent(name string) SpanAssert {
+	a.t.Helper()
+
+	for _, event := range a.span.Events {
+		if event.Name == name {
+			return a
+		}
+	}
+
+	a.t.Errorf("pocotest: span %q has no event %q", a.span.Name, name)
+
+	return a
+}
+
+// HasError checks that an error matching target with errors.Is was recorded
+// in the span.
+func (a SpanAssert) HasError(target error) SpanAssert {
+	a.t.Helper()
+
+	for _, err := range a.span.Errors {
+		if errors.Is(err, target) {
+			return a
+		}
+	}
+
+	a.t.Errorf("pocotest: span %q has no error matching %v, got %v", a.span.Name, target, a.span.Errors)
+
+	return a
+}
+
+// HasStatus checks the status of the span.
+func (a SpanAssert) HasStatus(status poco.SpanStatus) SpanAssert {
+	a.t.Helper()
+
+	if a.span.Status != status {
+		a.t.Errorf("pocotest: span %q status should be %s, got %s", a.span.Name, status, a.span.Status)
+	}
+
+	return a
+}
+
+// IsEnded checks that the span was ended.
+func (a SpanAssert) IsEnded() SpanAssert {
+	a.t.Helper()
+
+	if !a.span.Ended() {
+		a.t.Errorf("pocotest: span %q is not ended", a.span.Name)
+	}
+
+	return a
+}
+
+// AssertEvent checks that an event named name was captured, in a span or not.
+func (r *Recorder) AssertEvent(t testing.TB, name string) {
+	t.Helper()
+
+	for _, event := range r.Events() {
+		if event.Name == name {
+			return
+		}
+	}
+
+	t.Errorf("pocotest: event %q not found", name)
+}
+
+// AssertError checks that an error matching target with errors.Is was captured.
+func (r *Recorder) AssertError(t testing.TB, target error) {
+	t.Helper()
+
+	for _, e := range r.Errors() {
+		if errors.Is(e.Err, target) {
+			return
+		}
+	}
+
+	t.Errorf("pocotest: no error matching %v", target)
+}
+
+// AssertNoErrors checks that no error was captured.
+func (r *Recorder) AssertNoErrors(t testing.TB) {
+	t.Helper()
+
+	for _, e := range r.Errors() {
+		t.Errorf("pocotest: unexpected error %v", e.Err)
+	}
+}
+
+// AssertGolden compares the Tree of the recorder with the content of the file
+// at path. With POCOTEST_UPDATE=1, the file is written instead.
+func (r *Recorder) AssertGolden(t testing.TB, path string, ignore ...string) {
+	t.Helper()
+
+	actual := r.Tree(ignore...)
+
+	if os.Getenv(UpdateEnv) == "1" {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("pocotest: %v", err)
+		}
+
+		if err := os.WriteFile(path, []byte(actual), 0o644); err != nil {
+			t.Fatalf("pocotest: %v", err)
+		}
+
+		return
+	}
+
+	expected, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("pocotest: %v (run with %s=1 to create it)", err, UpdateEnv)
+	}
+
+	if strings.ReplaceAll(string(expected), "\r\n", "\n") != actual {
+		t.Errorf("pocotest: tree does not match %s\nexpected:\n%s\nactual:\n%s", path, expected, actual)
+	}
+}
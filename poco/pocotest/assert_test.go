@@ -0,0 +1,132 @@
+package pocotest_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/Arsfiqball/talker/poco"
+	"github.com/Arsfiqball/talker/poco/pocotest"
+)
+
+// fakeT records failures instead of failing the test.
+type fakeT struct {
+	testing.TB
+	failures []string
+	fatal    bool
+}
+
+func (f *fakeT) Helper() {}
+
+func (f *fakeT) Errorf(format string, args ...any) {
+	f.failures = append(f.failures, fmt.Sprintf(format, args...))
+}
+
+func (f *fakeT) Fatalf(format string, args ...any) {
+	f.Errorf(format, args...)
+	f.fatal = true
+	runtime.Goexit()
+}
+
+func run(fn func(t *fakeT)) *fakeT {
+	ft := &fakeT{}
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+		fn(ft)
+	}()
+
+	<-done
+
+	return ft
+}
+
+func TestAssertions(t *testing.T) {
+	rec := pocotest.NewRecorder()
+	instrumented(rec.Context(context.Background()))
+
+	t.Run("should pass on matching span tree", func(t *testing.T) {
+		rec.AssertSpan(t, "handle request").
+			IsEnded().
+			HasStatus(poco.SpanStatusError).
+			HasAttr("http.status_code", 404).
+			HasChild("find user").
+			HasAttr("user.id", 42).
+			HasAttr("db.table", "users").
+			HasEvent("cache miss").
+			HasError(errNotFound)
+
+		rec.AssertEvent(t, "cache miss")
+		rec.AssertError(t, errNotFound)
+	})
+
+	t.Run("should report mismatches", func(t *testing.T) {
+		ft := run(func(ft *fakeT) {
+			rec.AssertSpan(ft, "handle request").
+				HasStatus(poco.SpanStatusOK).
+				HasAttr("http.status_code", 200).
+				HasAttr("missing", 1).
+				HasEvent("missing").
+				HasError(errors.New("other"))
+
+			rec.AssertEvent(ft, "missing")
+			rec.AssertNoErrors(ft)
+		})
+
+		if len(ft.failures) != 7 || ft.fatal {
+			t.Errorf("every mismatch should be reported, got %q", ft.failures)
+		}
+	})
+
+	t.Run("should stop on missing spans", func(t *testing.T) {
+		for _, fn := range []func(ft *fakeT){
+			func(ft *fakeT) { rec.AssertSpan(ft, "missing") },
+			func(ft *fakeT) { rec.AssertSpan(ft, "handle request").HasChild("missing") },
+		} {
+			ft := run(fn)
+
+			if !ft.fatal || !strings.Contains(ft.failures[0], "missing") {
+				t.Errorf("missing span should be fatal, got %q", ft.failures)
+			}
+		}
+	})
+
+	t.Run("should report golden mismatch", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "tree.golden")
+
+		if err := os.WriteFile(path, []byte("span other\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		ft := run(func(ft *fakeT) {
+			rec.AssertGolden(ft, path)
+		})
+
+		if len(ft.failures) != 1 || !strings.Contains(ft.failures[0], "span handle request") {
+			t.Errorf("mismatch should be reported, got %q", ft.failures)
+		}
+	})
+
+	t.Run("should write golden file on update", func(t *testing.T) {
+		t.Setenv(pocotest.UpdateEnv, "1")
+
+		path := filepath.Join(t.TempDir(), "nested", "tree.golden")
+
+		rec.AssertGolden(t, path)
+
+		b, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if string(b) != rec.Tree() {
+			t.Errorf("golden file should hold the tree, got %q", b)
+		}
+	})
+}
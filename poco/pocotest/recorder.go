@@ -0,0 +1,400 @@
+// Package pocotest provides helpers to test poco instrumentation.
+package pocotest
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Arsfiqball/talker/poco"
+)
+
+// Span is a span captured by a Recorder. Its fields must only be read once
+// the instrumented code is done.
+type Span struct {
+	Name              string
+	Attrs             []any // Attrs holds the start attributes followed by the ones set later, as poco.Attr values.
+	Events            []Event
+	Errors            []error
+	Status            poco.SpanStatus
+	StatusDescription string
+	Start             time.Time
+	End               time.Time // End is zero while the span is not ended.
+	Parent            *Span
+	Children          []*Span
+}
+
+// Ended reports whether the span was ended.
+func (s *Span) Ended() bool {
+	return !s.End.IsZero()
+}
+
+// Duration returns the time between the start and the end of the span, or 0
+// while it is not ended.
+func (s *Span) Duration() time.Duration {
+	if !s.Ended() {
+		return 0
+	}
+
+	return s.End.Sub(s.Start)
+}
+
+// Attr returns the value of the last attribute named key. Attributes of
+// groups are named with dots, e.g. "request.method".
+func (s *Span) Attr(key string) (any, bool) {
+	return lookupAttr(s.Attrs, key)
+}
+
+// Child returns the first direct child named name, or nil.
+func (s *Span) Child(name string) *Span {
+	for _, child := range s.Children {
+		if child.Name == name {
+			return child
+		}
+	}
+
+	return nil
+}
+
+// Event is an event captured by a Recorder, either told to the observer or
+// added to a span handle.
+type Event struct {
+	Name  string
+	Attrs []any
+	Span  *Span // Span is the span the event belongs to, or nil.
+}
+
+// Attr returns the value of the last attribute named key.
+func (e Event) Attr(key string) (any, bool) {
+	return lookupAttr(e.Attrs, key)
+}
+
+// RecordedError is an error captured by a Recorder, either told to the
+// observer or recorded on a span handle.
+type RecordedError struct {
+	Err  error
+	Span *Span // Span is the span the error belongs to, or nil.
+}
+
+type spanKey struct{}
+
+// Recorder is a listener capturing spans as a tree, events and errors in
+// memory. It implements poco.SpanHandleListener, poco.SpanListener,
+// poco.EventListener and poco.ErrorListener, and is safe for concurrent use.
+type Recorder struct {
+	mu     sync.Mutex
+	roots  []*Span
+	spans  []*Span
+	events []Event
+	errs   []RecordedError
+}
+
+func NewRecorder() *Recorder {
+	return &Recorder{}
+}
+
+// Observer returns an observer reporting to the recorder, with opts applied.
+func (r *Recorder) Observer(opts ...poco.ObserverOption) *poco.Observer {
+	return poco.NewObserver(append([]poco.ObserverOption{poco.WithListener(r)}, opts...)...)
+}
+
+// Context returns a copy of ctx carrying an observer reporting to the recorder.
+func (r *Recorder) Context(ctx context.Context) context.Context {
+	return poco.WithObserver(ctx, r.Observer())
+}
+
+func spanFrom(ctx context.Context) *Span {
+	span, _ := ctx.Value(spanKey{}).(*Span)
+	return span
+}
+
+func (r *Recorder) OnSpanStart(ctx context.Context, name string, attrs []any) (context.Context, poco.SpanRecorder) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	span := &Span{
+		Name:   name,
+		Attrs:  typedAttrs(attrs),
+		Start:  time.Now(),
+		Parent: spanFrom(ctx),
+	}
+
+	if span.Parent != nil {
+		span.Parent.Children = append(span.Parent.Children, span)
+	} else {
+		r.roots = append(r.roots, span)
+	}
+
+	r.spans = append(r.spans, span)
+
+	return context.WithValue(ctx, spanKey{}, span), &spanRecorder{recorder: r, span: span}
+}
+
+func (r *Recorder) OnSpan(ctx context.Context, name string, attrs []any) (context.Context, poco.SpanEnd) {
+	ctx, recorder := r.OnSpanStart(ctx, name, attrs)
+
+	return ctx, recorder.End
+}
+
+func (r *Recorder) OnEvent(ctx context.Context, name string, attrs []any) {
+	r.addEvent(spanFrom(ctx), name, attrs)
+}
+
+func (r *Recorder) OnError(ctx context.Context, err error) error {
+	r.addError(spanFrom(ctx), err)
+
+	return err
+}
+
+func (r *Recorder) addEvent(span *Span, name string, attrs []any) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	event := Event{Name: name, Attrs: append([]any(nil), attrs...), Span: span}
+
+	if span != nil {
+		span.Events = append(span.Events, event)
+	}
+
+	r.events = append(r.events, event)
+}
+
+func (r *Recorder) addError(span *Span, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if span != nil {
+		span.Errors = append(span.Errors, err)
+	}
+
+	r.errs = append(r.errs, RecordedError{Err: err, Span: span})
+}
+
+// Roots returns the spans without parent, in start order.
+func (r *Recorder) Roots() []*Span {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]*Span(nil), r.roots...)
+}
+
+// Spans returns all spans, in start order.
+func (r *Recorder) Spans() []*Span {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]*Span(nil), r.spans...)
+}
+
+// Span returns the first span named name, or nil.
+func (r *Recorder) Span(name string) *Span {
+	for _, span := range r.Spans() {
+		if span.Name == name {
+			return span
+		}
+	}
+
+	return nil
+}
+
+// Events returns all events, in order.
+func (r *Recorder) Events() []Event {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]Event(nil), r.events...)
+}
+
+// Errors returns all errors, in order.
+func (r *Recorder) Errors() []RecordedError {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]RecordedError(nil), r.errs...)
+}
+
+// Reset forgets everything captured so far.
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.roots, r.spans, r.events, r.errs = nil, nil, nil, nil
+}
+
+// Tree renders the captured spans, events and errors, one per line and
+// indented by depth, for golden-file comparison. Durations and times are
+// rendered as placeholders and the attributes named in ignore are left out,
+// so the rendering is stable across runs.
+func (r *Recorder) Tree(ignore ...string) string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	ignored := map[string]bool{}
+
+	for _, key := range ignore {
+		ignored[key] = true
+	}
+
+	var b strings.Builder
+
+	for _, span := range r.roots {
+		writeSpan(&b, span, "", ignored)
+	}
+
+	for _, event := range r.events {
+		if event.Span == nil {
+			fmt.Fprintf(&b, "event %s%s\n", event.Name, renderAttrs(event.Attrs, ignored))
+		}
+	}
+
+	for _, e := range r.errs {
+		if e.Span == nil {
+			fmt.Fprintf(&b, "error %s\n", e.Err)
+		}
+	}
+
+	return b.String()
+}
+
+func writeSpan(b *strings.Builder, span *Span, indent string, ignored map[string]bool) {
+	fmt.Fprintf(b, "%sspan %s%s", indent, span.Name, renderAttrs(span.Attrs, ignored))
+
+	if span.Status != poco.SpanStatusUnset {
+		fmt.Fprintf(b, " [%s]", span.Status)
+	}
+
+	if !span.Ended() {
+		b.WriteString(" (not ended)")
+	}
+
+	b.WriteString("\n")
+
+	for _, event := range span.Events {
+		fmt.Fprintf(b, "%s  event %s%s\n", indent, event.Name, renderAttrs(event.Attrs, ignored))
+	}
+
+	for _, err := range span.Errors {
+		fmt.Fprintf(b, "%s  error %s\n", indent, err)
+	}
+
+	for _, child := range span.Children {
+		writeSpan(b, child, indent+"  ", ignored)
+	}
+}
+
+func renderAttrs(attrs []any, ignored map[string]bool) string {
+	var b strings.Builder
+
+	for _, attr := range flattenAttrs(attrs) {
+		if ignored[attr.Key] {
+			continue
+		}
+
+		value := attr.Value
+
+		switch value.Kind() {
+		case slog.KindDuration:
+			value = slog.StringValue("<duration>")
+		case slog.KindTime:
+			value = slog.StringValue("<time>")
+		}
+
+		fmt.Fprintf(&b, " %s=%s", attr.Key, value)
+	}
+
+	return b.String()
+}
+
+// flattenAttrs parses attrs like poco.ParseAttrs, naming the attributes of
+// groups with dots.
+func flattenAttrs(attrs []any) []slog.Attr {
+	parsed, _ := poco.ParseAttrs(attrs)
+
+	var flatten func(prefix string, attrs []slog.Attr) []slog.Attr
+
+	flatten = func(prefix string, attrs []slog.Attr) []slog.Attr {
+		result := []slog.Attr{}
+
+		for _, attr := range attrs {
+			key := prefix + attr.Key
+			value := attr.Value.Resolve()
+
+			if value.Kind() == slog.KindGroup {
+				result = append(result, flatten(key+".", value.Group())...)
+				continue
+			}
+
+			result = append(result, slog.Attr{Key: key, Value: value})
+		}
+
+		return result
+	}
+
+	return flatten("", parsed)
+}
+
+// typedAttrs converts attrs into poco.Attr values, so the attributes set in
+// several calls do not pair with each other when one call is malformed.
+func typedAttrs(attrs []any) []any {
+	parsed, _ := poco.ParseAttrs(attrs)
+	return poco.Attrs(parsed...)
+}
+
+func lookupAttr(attrs []any, key string) (any, bool) {
+	var (
+		value any
+		found bool
+	)
+
+	for _, attr := range flattenAttrs(attrs) {
+		if attr.Key == key {
+			value, found = attr.Value.Any(), true
+		}
+	}
+
+	return value, found
+}
+
+type spanRecorder struct {
+	recorder *Recorder
+	span     *Span
+}
+
+func (s *spanRecorder) SetAttributes(attrs []any) {
+	s.recorder.mu.Lock()
+	defer s.recorder.mu.Unlock()
+
+	s.span.Attrs = append(s.span.Attrs, typedAttrs(attrs)...)
+}
+
+func (s *spanRecorder) AddEvent(name string, attrs []any) {
+	s.recorder.addEvent(s.span, name, attrs)
+}
+
+func (s *spanRecorder) RecordError(err error) {
+	s.recorder.addError(s.span, err)
+}
+
+func (s *spanRecorder) SetStatus(status poco.SpanStatus, description string) {
+	s.recorder.mu.Lock()
+	defer s.recorder.mu.Unlock()
+
+	s.span.Status = status
+	s.span.StatusDescription = description
+}
+
+func (s *spanRecorder) End() {
+	s.EndAt(time.Now())
+}
+
+func (s *spanRecorder) EndAt(t time.Time) {
+	s.recorder.mu.Lock()
+	defer s.recorder.mu.Unlock()
+
+	if !s.span.Ended() {
+		s.span.End = t
+	}
+}
@@ -0,0 +1,121 @@
+package pocotest_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Arsfiqball/talker/poco"
+	"github.com/Arsfiqball/talker/poco/pocotest"
+)
+
+var errNotFound = poco.NewError("NOT_FOUND", "user not found")
+
+func instrumented(ctx context.Context) {
+	ctx, span := poco.StartSpan(ctx, "handle request", []any{"http.method", "GET"})
+	defer span.End()
+
+	func() {
+		ctx, end := poco.Span(ctx, "find user", poco.Attrs(
+			poco.Int("user.id", 42),
+			poco.Group("db", poco.String("table", "users")),
+			poco.Duration("timeout", time.Second),
+		))
+		defer end()
+
+		poco.Event(ctx, "cache miss", []any{"key", "user:42"})
+		_ = poco.Err(ctx, errNotFound.Wrap())
+	}()
+
+	span.SetAttributes([]any{"http.status_code", 404})
+	span.SetStatus(poco.SpanStatusError, "not found")
+
+	poco.Event(context.Background(), "ignored", nil)
+}
+
+func TestRecorder(t *testing.T) {
+	t.Run("should capture spans as a tree", func(t *testing.T) {
+		rec := pocotest.NewRecorder()
+		ctx := rec.Context(context.Background())
+
+		instrumented(ctx)
+		poco.Event(ctx, "done", nil)
+
+		roots := rec.Roots()
+
+		if len(roots) != 1 || len(rec.Spans()) != 2 {
+			t.Fatalf("unexpected spans %v", rec.Tree())
+		}
+
+		root := roots[0]
+		child := root.Child("find user")
+
+		if child == nil || child.Parent != root {
+			t.Fatalf("find user should be a child of handle request\n%s", rec.Tree())
+		}
+
+		if !root.Ended() || root.Duration() <= 0 || root.Duration() < child.Duration() {
+			t.Errorf("unexpected durations %s and %s", root.Duration(), child.Duration())
+		}
+
+		if v, ok := root.Attr("http.status_code"); !ok || v != int64(404) {
+			t.Errorf("unexpected attribute %v", v)
+		}
+
+		if v, ok := child.Attr("db.table"); !ok || v != "users" {
+			t.Errorf("group attribute should be flattened, got %v", v)
+		}
+
+		if len(child.Events) != 1 || child.Events[0].Span != child {
+			t.Errorf("event should belong to find user, got %+v", child.Events)
+		}
+
+		if errs := rec.Errors(); len(errs) != 1 || errs[0].Span != child || !errors.Is(errs[0].Err, errNotFound) {
+			t.Errorf("error should belong to find user, got %+v", errs)
+		}
+
+		if events := rec.Events(); len(events) != 2 || events[1].Span != nil {
+			t.Errorf("event outside spans should be captured, got %+v", events)
+		}
+	})
+
+	t.Run("should compare with golden file", func(t *testing.T) {
+		rec := pocotest.NewRecorder()
+		ctx := rec.Context(context.Background())
+
+		instrumented(ctx)
+		poco.Event(ctx, "done", []any{"at", time.Now()})
+
+		rec.AssertGolden(t, "testdata/tree.golden", "http.method")
+	})
+
+	t.Run("should render unfinished spans", func(t *testing.T) {
+		rec := pocotest.NewRecorder()
+
+		_, _ = rec.Observer().StartSpan(context.Background(), "pending", nil)
+
+		if tree := rec.Tree(); tree != "span pending (not ended)\n" {
+			t.Errorf("unexpected tree %q", tree)
+		}
+
+		rec.Reset()
+
+		if len(rec.Spans()) != 0 || rec.Tree() != "" {
+			t.Error("recorder should be reset")
+		}
+	})
+
+	t.Run("should support legacy span listeners", func(t *testing.T) {
+		rec := pocotest.NewRecorder()
+
+		var listener poco.SpanListener = rec
+
+		_, end := listener.OnSpan(context.Background(), "legacy", nil)
+		end()
+
+		if span := rec.Span("legacy"); span == nil || !span.Ended() {
+			t.Error("legacy span should be captured")
+		}
+	})
+}
@@ -1,13 +1,20 @@
 package poco
 
 import (
+	"context"
 	"fmt"
+	"net/http"
 	"runtime"
+	"strings"
 )
 
+// RecoveredPanic is a panic recovered by Recover. It implements error and
+// unwraps to the panic value when it is an error, so errors.Is and errors.As
+// see through it.
 type RecoveredPanic struct {
 	message string
 	stack   []string
+	value   any
 }
 
 func (e RecoveredPanic) Message() string {
@@ -18,31 +25,134 @@ func (e RecoveredPanic) Stack() []string {
 	return e.stack
 }
 
-func Recover(e *RecoveredPanic) {
+// Value returns the original value given to panic.
+func (e RecoveredPanic) Value() any {
+	return e.value
+}
+
+// Panicked reports whether a panic was recovered.
+func (e RecoveredPanic) Panicked() bool {
+	return e.value != nil
+}
+
+func (e RecoveredPanic) Error() string {
+	return "panic: " + e.message
+}
+
+func (e RecoveredPanic) Unwrap() error {
+	err, _ := e.value.(error)
+	return err
+}
+
+type recoverConfig struct {
+	depth   int
+	ctx     context.Context
+	repanic bool
+}
+
+type RecoverOption func(*recoverConfig)
+
+// WithRecoverDepth sets the maximum number of stack frames kept, 32 by
+// default. A depth of 0 or less keeps the full stack.
+func WithRecoverDepth(depth int) RecoverOption {
+	return func(c *recoverConfig) {
+		c.depth = depth
+	}
+}
+
+// WithRecoverReport reports the recovered panic to the error listeners of the
+// observer carried by ctx.
+func WithRecoverReport(ctx context.Context) RecoverOption {
+	return func(c *recoverConfig) {
+		c.ctx = ctx
+	}
+}
+
+// WithRepanic panics again with the original value once the panic is recorded
+// and reported.
+func WithRepanic() RecoverOption {
+	return func(c *recoverConfig) {
+		c.repanic = true
+	}
+}
+
+// Recover records a panic into e and must be deferred directly. The panic
+// http.ErrAbortHandler, used to abort an HTTP handler on purpose, is never
+// recovered. runtime.Goexit is not a panic either: e is left untouched and
+// the goroutine keeps exiting.
+func Recover(e *RecoveredPanic, opts ...RecoverOption) {
 	if e == nil {
 		return
 	}
 
-	skip := 2
-	depth := 10
+	r := recover()
 
-	if r := recover(); r != nil {
-		e.message = fmt.Sprintf("%v", r)
+	if r == nil {
+		return
+	}
 
-		for i := skip; i < depth-1; i++ {
-			pc, file, line, ok := runtime.Caller(i)
-			if !ok {
+	if r == http.ErrAbortHandler {
+		panic(r)
+	}
+
+	cfg := recoverConfig{depth: maxStackDepth}
+
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	e.value = r
+	e.message = fmt.Sprintf("%v", r)
+	e.stack = panicStack(cfg.depth)
+
+	if cfg.ctx != nil {
+		_ = Err(cfg.ctx, *e)
+	}
+
+	if cfg.repanic {
+		panic(r)
+	}
+}
+
+// panicStack returns the stack of the panicking goroutine, starting at the
+// function which panicked.
+func panicStack(depth int) []string {
+	pcs := make([]uintptr, 64)
+
+	for {
+		n := runtime.Callers(3, pcs)
+
+		if n < len(pcs) {
+			pcs = pcs[:n]
+			break
+		}
+
+		pcs = make([]uintptr, len(pcs)*2)
+	}
+
+	stack := []string{}
+	frames := runtime.CallersFrames(pcs)
+	inRuntime := true
+
+	for {
+		frame, more := frames.Next()
+
+		if inRuntime && strings.HasPrefix(frame.Function, "runtime.") {
+			if !more {
 				break
 			}
 
-			name := "unknown"
+			continue
+		}
 
-			fn := runtime.FuncForPC(pc)
-			if fn != nil {
-				name = fn.Name()
-			}
+		inRuntime = false
 
-			e.stack = append(e.stack, fmt.Sprintf("%s:%d %s", file, line, name))
+		stack = append(stack, fmt.Sprintf("%s:%d %s", frame.File, frame.Line, frame.Function))
+
+		if !more || (depth > 0 && len(stack) == depth) {
+			break
 		}
 	}
+
+	return stack
 }
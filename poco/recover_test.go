@@ -1,6 +1,12 @@
 package poco_test
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"runtime"
+	"strings"
 	"testing"
 
 	"github.com/Arsfiqball/talker/poco"
@@ -48,3 +54,134 @@ func TestRecover(t *testing.T) {
 		// }
 	})
 }
+
+var errBoom = errors.New("boom")
+
+type recoverErrorListener struct {
+	errs []error
+}
+
+func (l *recoverErrorListener) OnError(ctx context.Context, err error) error {
+	l.errs = append(l.errs, err)
+	return err
+}
+
+func TestRecoverOptions(t *testing.T) {
+	t.Run("should keep the original value", func(t *testing.T) {
+		var rp poco.RecoveredPanic
+
+		func() {
+			defer poco.Recover(&rp)
+
+			panic(fmt.Errorf("wrapped: %w", errBoom))
+		}()
+
+		if !rp.Panicked() {
+			t.Fatal("panic should be recovered")
+		}
+
+		var err error = rp
+
+		if !errors.Is(err, errBoom) {
+			t.Error("recovered error should match errors.Is")
+		}
+
+		if err.Error() != "panic: wrapped: boom" {
+			t.Errorf("unexpected error message %q", err.Error())
+		}
+
+		if _, ok := rp.Value().(error); !ok {
+			t.Errorf("value should keep its type, got %T", rp.Value())
+		}
+
+		if !strings.Contains(rp.Stack()[0], "recover_test.go") {
+			t.Errorf("stack should start at the panicking function, got %v", rp.Stack())
+		}
+	})
+
+	t.Run("should limit the stack depth", func(t *testing.T) {
+		var limited, full poco.RecoveredPanic
+
+		func() {
+			defer poco.Recover(&limited, poco.WithRecoverDepth(2))
+
+			someProxyFunc()
+		}()
+
+		func() {
+			defer poco.Recover(&full, poco.WithRecoverDepth(0))
+
+			someProxyFunc()
+		}()
+
+		if len(limited.Stack()) != 2 {
+			t.Errorf("stack should have 2 frames, got %v", limited.Stack())
+		}
+
+		if len(full.Stack()) <= 4 {
+			t.Errorf("stack should be complete, got %v", full.Stack())
+		}
+	})
+
+	t.Run("should report and repanic", func(t *testing.T) {
+		listener := &recoverErrorListener{}
+		ctx := poco.WithObserver(context.Background(), poco.NewObserver(poco.WithListener(listener)))
+
+		var rp poco.RecoveredPanic
+
+		repanicked := func() (value any) {
+			defer func() {
+				value = recover()
+			}()
+
+			defer poco.Recover(&rp, poco.WithRecoverReport(ctx), poco.WithRepanic())
+
+			panic(errBoom)
+		}()
+
+		if repanicked != errBoom {
+			t.Errorf("original value should be panicked again, got %v", repanicked)
+		}
+
+		if len(listener.errs) != 1 || !errors.Is(listener.errs[0], errBoom) {
+			t.Errorf("panic should be reported, got %v", listener.errs)
+		}
+	})
+
+	t.Run("should not recover aborted handlers", func(t *testing.T) {
+		var rp poco.RecoveredPanic
+
+		value := func() (value any) {
+			defer func() {
+				value = recover()
+			}()
+
+			defer poco.Recover(&rp)
+
+			panic(http.ErrAbortHandler)
+		}()
+
+		if value != http.ErrAbortHandler || rp.Panicked() {
+			t.Errorf("http.ErrAbortHandler should not be recovered, got %v", value)
+		}
+	})
+
+	t.Run("should let runtime.Goexit through", func(t *testing.T) {
+		var rp poco.RecoveredPanic
+
+		done := make(chan struct{})
+
+		go func() {
+			defer close(done)
+			defer poco.Recover(&rp)
+
+			runtime.Goexit()
+		}()
+
+		<-done
+
+		if rp.Panicked() {
+			t.Error("runtime.Goexit should not be recorded as panic")
+		}
+	})
+}
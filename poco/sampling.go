@@ -0,0 +1,175 @@
+package poco
+
+import (
+	"context"
+	"log/slog"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+)
+
+type sampledKey struct{}
+
+// Sampler decides whether a span is reported to the listeners.
+type Sampler interface {
+	ShouldSample(ctx context.Context, name string, attrs []any) bool
+}
+
+type SamplerFunc func(ctx context.Context, name string, attrs []any) bool
+
+func (f SamplerFunc) ShouldSample(ctx context.Context, name string, attrs []any) bool {
+	return f(ctx, name, attrs)
+}
+
+// Sampled returns the sampling decision of the span in ctx, ok is false when
+// no decision was made.
+func Sampled(ctx context.Context) (sampled bool, ok bool) {
+	sampled, ok = ctx.Value(sampledKey{}).(bool)
+	return sampled, ok
+}
+
+func AlwaysSample() Sampler {
+	return SamplerFunc(func(ctx context.Context, name string, attrs []any) bool {
+		return true
+	})
+}
+
+func NeverSample() Sampler {
+	return SamplerFunc(func(ctx context.Context, name string, attrs []any) bool {
+		return false
+	})
+}
+
+// RatioSampler samples the given fraction of spans, between 0 and 1.
+func RatioSampler(ratio float64) Sampler {
+	return SamplerFunc(func(ctx context.Context, name string, attrs []any) bool {
+		return rand.Float64() < ratio
+	})
+}
+
+// ParentBasedSampler follows the decision made for the parent span, local or
+// received through traceparent, and uses root for spans without parent.
+func ParentBasedSampler(root Sampler) Sampler {
+	return SamplerFunc(func(ctx context.Context, name string, attrs []any) bool {
+		if sampled, ok := Sampled(ctx); ok {
+			return sampled
+		}
+
+		if sc, ok := SpanContextFrom(ctx); ok && sc.Remote {
+			return sc.Sampled
+		}
+
+		return root.ShouldSample(ctx, name, attrs)
+	})
+}
+
+type rateLimitedSampler struct {
+	mu     sync.Mutex
+	rate   float64 // tokens per nanosecond
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// RateLimitedSampler samples at most n spans per period.
+func RateLimitedSampler(n int, per time.Duration) Sampler {
+	return &rateLimitedSampler{
+		rate:   float64(n) / float64(per),
+		burst:  float64(n),
+		tokens: float64(n),
+		last:   time.Now(),
+	}
+}
+
+func (s *rateLimitedSampler) ShouldSample(ctx context.Context, name string, attrs []any) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+
+	s.tokens += float64(now.Sub(s.last)) * s.rate
+	s.last = now
+
+	if s.tokens > s.burst {
+		s.tokens = s.burst
+	}
+
+	if s.tokens < 1 {
+		return false
+	}
+
+	s.tokens--
+
+	return true
+}
+
+// Filter decides whether a span or an event is kept, before sampling.
+type Filter func(name string, attrs []any) bool
+
+// DropNamePrefix drops spans or events whose name starts with one of prefixes.
+func DropNamePrefix(prefixes ...string) Filter {
+	return func(name string, attrs []any) bool {
+		for _, prefix := range prefixes {
+			if strings.HasPrefix(name, prefix) {
+				return false
+			}
+		}
+
+		return true
+	}
+}
+
+// DropAttr drops spans or events having the attribute key set to value.
+func DropAttr(key string, value any) Filter {
+	expected := slog.AnyValue(value)
+
+	return func(name string, attrs []any) bool {
+		parsed, _ := ParseAttrs(attrs)
+
+		for _, attr := range parsed {
+			if attr.Key == key && attr.Value.Equal(expected) {
+				return false
+			}
+		}
+
+		return true
+	}
+}
+
+// WithSampler sets the sampler deciding which spans are reported. The decision
+// is stored in the span ctx, so events of an unsampled span are dropped as
+// well and ParentBasedSampler can apply it to child spans. Errors are always
+// reported.
+func WithSampler(sampler Sampler) ObserverOption {
+	return func(o *Observer) error {
+		o.sampler = sampler
+		return nil
+	}
+}
+
+// WithSpanFilter adds a filter applied to spans before sampling.
+func WithSpanFilter(filter Filter) ObserverOption {
+	return func(o *Observer) error {
+		o.spanFilters = append(o.spanFilters, filter)
+		return nil
+	}
+}
+
+// WithEventFilter adds a filter applied to events.
+func WithEventFilter(filter Filter) ObserverOption {
+	return func(o *Observer) error {
+		o.eventFilters = append(o.eventFilters, filter)
+		return nil
+	}
+}
+
+func keep(filters []Filter, name string, attrs []any) bool {
+	for _, filter := range filters {
+		if !filter(name, attrs) {
+			return false
+		}
+	}
+
+	return true
+}
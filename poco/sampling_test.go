@@ -0,0 +1,98 @@
+package poco_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Arsfiqball/talker/poco"
+)
+
+func TestSampler(t *testing.T) {
+	t.Run("never sample drops spans and their events", func(t *testing.T) {
+		listener := &countingListener{}
+		obs := poco.NewObserver(poco.WithListener(listener), poco.WithSampler(poco.NeverSample()))
+
+		ctx, end := obs.Span(context.Background(), "span", nil)
+		obs.Event(ctx, "event", nil)
+		end()
+
+		if sampled, ok := poco.Sampled(ctx); !ok || sampled {
+			t.Error("ctx should carry a negative sampling decision")
+		}
+
+		if listener.spans != 0 || listener.events != 0 {
+			t.Errorf("nothing should be reported, got %+v", listener)
+		}
+
+		_ = obs.Error(ctx, context.Canceled)
+
+		if listener.errs != 1 {
+			t.Error("errors should always be reported")
+		}
+	})
+
+	t.Run("parent based sampler follows parent decision", func(t *testing.T) {
+		listener := &countingListener{}
+		root := poco.NewObserver(poco.WithSampler(poco.NeverSample()))
+		obs := poco.NewObserver(poco.WithListener(listener), poco.WithSampler(poco.ParentBasedSampler(poco.AlwaysSample())))
+
+		ctx, _ := root.Span(context.Background(), "root", nil)
+		obs.Span(ctx, "child", nil)
+
+		if listener.spans != 0 {
+			t.Errorf("child of unsampled span should not be sampled, got %d", listener.spans)
+		}
+
+		obs.Span(context.Background(), "root", nil)
+
+		if listener.spans != 1 {
+			t.Errorf("root span should be sampled, got %d", listener.spans)
+		}
+	})
+
+	t.Run("ratio sampler", func(t *testing.T) {
+		all := &countingListener{}
+		none := &countingListener{}
+
+		poco.NewObserver(poco.WithListener(all), poco.WithSampler(poco.RatioSampler(1))).Span(context.Background(), "span", nil)
+		poco.NewObserver(poco.WithListener(none), poco.WithSampler(poco.RatioSampler(0))).Span(context.Background(), "span", nil)
+
+		if all.spans != 1 || none.spans != 0 {
+			t.Errorf("unexpected counts %d and %d", all.spans, none.spans)
+		}
+	})
+
+	t.Run("rate limited sampler", func(t *testing.T) {
+		listener := &countingListener{}
+		obs := poco.NewObserver(poco.WithListener(listener), poco.WithSampler(poco.RateLimitedSampler(3, time.Hour)))
+
+		for i := 0; i < 10; i++ {
+			obs.Span(context.Background(), "span", nil)
+		}
+
+		if listener.spans != 3 {
+			t.Errorf("3 spans should be sampled, got %d", listener.spans)
+		}
+	})
+}
+
+func TestFilter(t *testing.T) {
+	t.Run("drop by name prefix and attribute", func(t *testing.T) {
+		listener := &countingListener{}
+		obs := poco.NewObserver(
+			poco.WithListener(listener),
+			poco.WithSpanFilter(poco.DropNamePrefix("health.")),
+			poco.WithEventFilter(poco.DropAttr("level", "debug")),
+		)
+
+		obs.Span(context.Background(), "health.live", nil)
+		obs.Span(context.Background(), "user.fetch", nil)
+		obs.Event(context.Background(), "noise", []any{"level", "debug"})
+		obs.Event(context.Background(), "signal", []any{poco.String("level", "info")})
+
+		if listener.spans != 1 || listener.events != 1 {
+			t.Errorf("unexpected counts %+v", listener)
+		}
+	})
+}
@@ -0,0 +1,132 @@
+package poco
+
+import (
+	"fmt"
+	"io"
+	"runtime"
+	"sync/atomic"
+)
+
+const maxStackDepth = 32
+
+var stackCaptureDisabled atomic.Bool
+
+// SetStackCapture enables or disables the capture of the full stack when an
+// Error is wrapped. It is enabled by default; disabling it saves the cost of
+// runtime.Callers in hot paths, leaving only the wrap location.
+func SetStackCapture(enabled bool) {
+	stackCaptureDisabled.Store(!enabled)
+}
+
+// callStack holds program counters, resolved into frames only when printed.
+type callStack struct {
+	pcs []uintptr
+}
+
+func captureStack(skip int) *callStack {
+	if stackCaptureDisabled.Load() {
+		return nil
+	}
+
+	pcs := make([]uintptr, maxStackDepth)
+	n := runtime.Callers(skip+2, pcs)
+
+	return &callStack{pcs: pcs[:n]}
+}
+
+func (s *callStack) frames() []string {
+	if s == nil {
+		return nil
+	}
+
+	result := []string{}
+	frames := runtime.CallersFrames(s.pcs)
+
+	for {
+		frame, more := frames.Next()
+
+		result = append(result, fmt.Sprintf("%s:%d %s", frame.File, frame.Line, frame.Function))
+
+		if !more {
+			break
+		}
+	}
+
+	return result
+}
+
+// Stack returns the stack captured when the error was wrapped, one
+// "file:line function" entry per frame like RecoveredPanic.Stack, or nil when
+// it was not captured.
+func (e Error) Stack() []string {
+	return e.stack.frames()
+}
+
+// Format implements fmt.Formatter. %s and %v print the message, %q the quoted
+// message, and %+v the code, message, data, locations and stacks of the whole
+// chain.
+func (e Error) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			e.writeDetails(s)
+			return
+		}
+
+		io.WriteString(s, e.message)
+	case 's':
+		io.WriteString(s, e.message)
+	case 'q':
+		fmt.Fprintf(s, "%q", e.message)
+	default:
+		fmt.Fprintf(s, "%%!%c(poco.Error=%s)", verb, e.message)
+	}
+}
+
+func (e Error) writeDetails(w io.Writer) {
+	writeDetails(w, e, "")
+}
+
+func writeDetails(w io.Writer, err error, indent string) {
+	pocoErr, ok := err.(Error)
+
+	switch {
+	case !ok && len(unwrapAll(err)) == 0:
+		fmt.Fprintf(w, "sentinel: %s", err)
+	case !ok:
+		if _, joined := err.(interface{ Unwrap() []error }); joined {
+			io.WriteString(w, "joined:")
+		} else {
+			fmt.Fprintf(w, "wrapped: %s", err)
+		}
+	default:
+		fmt.Fprintf(w, "%s: %s", pocoErr.code, pocoErr.message)
+
+		if pocoErr.data != nil {
+			fmt.Fprintf(w, "\n%s    data: %+v", indent, pocoErr.data)
+		}
+
+		if pocoErr.declaredAt != "" {
+			fmt.Fprintf(w, "\n%s    declared at %s", indent, pocoErr.declaredAt)
+		}
+
+		if pocoErr.wrappedAt != "" {
+			fmt.Fprintf(w, "\n%s    wrapped at %s", indent, pocoErr.wrappedAt)
+		}
+
+		for _, frame := range pocoErr.stack.frames() {
+			fmt.Fprintf(w, "\n%s        %s", indent, frame)
+		}
+	}
+
+	parents := unwrapAll(err)
+
+	if len(parents) > 1 {
+		indent += "    "
+	}
+
+	for _, parent := range parents {
+		fmt.Fprintf(w, "\n%scaused by: ", indent)
+		writeDetails(w, parent, indent)
+	}
+}
@@ -0,0 +1,76 @@
+package poco_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/Arsfiqball/talker/poco"
+)
+
+func wrapInHelper(err error) error {
+	return poco.NewError("HELPER", "helper failed").Wrap(err)
+}
+
+func TestErrorStack(t *testing.T) {
+	t.Run("capture stack at wrap time", func(t *testing.T) {
+		err := wrapInHelper(errors.New("test")).(poco.Error)
+
+		stack := err.Stack()
+
+		if len(stack) == 0 {
+			t.Fatal("stack should be captured")
+		}
+
+		if !strings.Contains(stack[0], "poco_test.wrapInHelper") {
+			t.Errorf("first frame should be wrapInHelper, got %s", stack[0])
+		}
+	})
+
+	t.Run("disable capture", func(t *testing.T) {
+		poco.SetStackCapture(false)
+		defer poco.SetStackCapture(true)
+
+		err := wrapInHelper(errors.New("test")).(poco.Error)
+
+		if err.Stack() != nil {
+			t.Error("stack should not be captured")
+		}
+	})
+
+	t.Run("format", func(t *testing.T) {
+		errOuter := poco.NewError("OUTER", "outer failed")
+		errInner := poco.NewError("INNER", "inner failed").SetData(map[string]int{"id": 42})
+
+		err := errOuter.Wrap(errInner.Wrap(errors.New("sql: no rows")))
+
+		if s := fmt.Sprintf("%v", err); s != "outer failed" {
+			t.Errorf("%%v should print the message, got %s", s)
+		}
+
+		if s := fmt.Sprintf("%s", err); s != "outer failed" {
+			t.Errorf("%%s should print the message, got %s", s)
+		}
+
+		if s := fmt.Sprintf("%q", err); s != `"outer failed"` {
+			t.Errorf("%%q should print the quoted message, got %s", s)
+		}
+
+		detailed := fmt.Sprintf("%+v", err)
+
+		for _, expected := range []string{
+			"OUTER: outer failed",
+			"caused by: INNER: inner failed",
+			"data: map[id:42]",
+			"declared at ",
+			"wrapped at ",
+			"stack_test.go:",
+			"caused by: sentinel: sql: no rows",
+		} {
+			if !strings.Contains(detailed, expected) {
+				t.Errorf("%%+v should contain %q, got:\n%s", expected, detailed)
+			}
+		}
+	})
+}
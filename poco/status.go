@@ -0,0 +1,254 @@
+package poco
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+)
+
+// GRPCCode is a gRPC status code. The values are the ones of
+// google.golang.org/grpc/codes, so a GRPCCode converts with codes.Code(c).
+type GRPCCode uint32
+
+const (
+	GRPCOK GRPCCode = iota
+	GRPCCanceled
+	GRPCUnknown
+	GRPCInvalidArgument
+	GRPCDeadlineExceeded
+	GRPCNotFound
+	GRPCAlreadyExists
+	GRPCPermissionDenied
+	GRPCResourceExhausted
+	GRPCFailedPrecondition
+	GRPCAborted
+	GRPCOutOfRange
+	GRPCUnimplemented
+	GRPCInternal
+	GRPCUnavailable
+	GRPCDataLoss
+	GRPCUnauthenticated
+)
+
+// Status describes how an error is presented to clients.
+type Status struct {
+	HTTP    int      // HTTP is the HTTP status code, 500 when zero.
+	GRPC    GRPCCode // GRPC is the gRPC status code, derived from HTTP when GRPCOK.
+	Type    string   // Type is the problem type URI, "about:blank" when empty.
+	Message string   // Message is the public message, the HTTP status text when empty.
+	Expose  bool     // Expose uses the message of the poco.Error matched by code as public message.
+}
+
+// Problem is an RFC 9457 problem details object.
+type Problem struct {
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Status   int    `json:"status"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+	Code     string `json:"code,omitempty"`
+}
+
+// httpToGRPC maps HTTP status codes to the gRPC code of the same meaning.
+var httpToGRPC = map[int]GRPCCode{
+	http.StatusBadRequest:          GRPCInvalidArgument,
+	http.StatusUnauthorized:        GRPCUnauthenticated,
+	http.StatusForbidden:           GRPCPermissionDenied,
+	http.StatusNotFound:            GRPCNotFound,
+	http.StatusConflict:            GRPCAlreadyExists,
+	http.StatusPreconditionFailed:  GRPCFailedPrecondition,
+	http.StatusTooManyRequests:     GRPCResourceExhausted,
+	499:                            GRPCCanceled,
+	http.StatusNotImplemented:      GRPCUnimplemented,
+	http.StatusServiceUnavailable:  GRPCUnavailable,
+	http.StatusGatewayTimeout:      GRPCDeadlineExceeded,
+	http.StatusInternalServerError: GRPCInternal,
+}
+
+// withDefaults fills in the HTTP status code when it is not set, and the gRPC
+// code since an error is never OK.
+func (s Status) withDefaults() Status {
+	if s.HTTP == 0 {
+		s.HTTP = http.StatusInternalServerError
+	}
+
+	if s.GRPC == GRPCOK {
+		s.GRPC = httpToGRPC[s.HTTP]
+
+		if s.GRPC == GRPCOK {
+			s.GRPC = GRPCUnknown
+		}
+	}
+
+	return s
+}
+
+type statusTarget struct {
+	target error
+	status Status
+}
+
+// StatusRegistry maps errors to transport statuses. Errors are matched by
+// poco.Error code first, from the outermost error of the chain, then by the
+// errors.Is targets in registration order.
+type StatusRegistry struct {
+	mu       sync.RWMutex
+	codes    map[string]Status
+	targets  []statusTarget
+	fallback Status
+}
+
+// NewStatusRegistry returns an empty registry, mapping every error to 500 Internal Server Error.
+func NewStatusRegistry() *StatusRegistry {
+	return &StatusRegistry{
+		codes:    map[string]Status{},
+		fallback: Status{HTTP: http.StatusInternalServerError, GRPC: GRPCInternal},
+	}
+}
+
+// DefaultStatusRegistry is the registry used by the package level status functions.
+var DefaultStatusRegistry = NewStatusRegistry()
+
+// Register maps target to status. A poco.Error or ErrorDef target is matched by code, any
+// other error with errors.Is.
+func (r *StatusRegistry) Register(target error, status Status) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	status = status.withDefaults()
+
+	if s, ok := target.(sentinel); ok {
+		r.codes[s.sentinel().code] = status
+		return
+	}
+
+	r.targets = append(r.targets, statusTarget{target: target, status: status})
+}
+
+// RegisterCode maps the poco.Error code to status.
+func (r *StatusRegistry) RegisterCode(code string, status Status) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.codes[code] = status.withDefaults()
+}
+
+// SetFallback sets the status of errors which are not registered.
+func (r *StatusRegistry) SetFallback(status Status) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.fallback = status.withDefaults()
+}
+
+// lookup returns the status of err, the poco.Error it was matched by code, if
+// any, and whether it was registered.
+func (r *StatusRegistry) lookup(err error) (Status, Error, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var (
+		matched    Status
+		matchedErr Error
+		found      bool
+	)
+
+	walkError(err, func(e error) bool {
+		if pocoErr, ok := e.(Error); ok {
+			if status, ok := r.codes[pocoErr.code]; ok {
+				matched, matchedErr, found = status, pocoErr, true
+				return false
+			}
+		}
+
+		return true
+	})
+
+	if found {
+		return matched, matchedErr, true
+	}
+
+	for _, t := range r.targets {
+		if errors.Is(err, t.target) {
+			return t.status, Error{}, true
+		}
+	}
+
+	return r.fallback, Error{}, false
+}
+
+// Status returns the status of err, and whether it was registered.
+func (r *StatusRegistry) Status(err error) (Status, bool) {
+	status, _, ok := r.lookup(err)
+	return status, ok
+}
+
+// Problem renders err as problem details. Only the registered public message,
+// or the message of an error marked as user visible, is exposed, never where
+// an error was declared or wrapped. The code is the one of the poco.Error
+// matched by code, so errors matched by an errors.Is target have no code.
+func (r *StatusRegistry) Problem(err error) Problem {
+	status, pocoErr, ok := r.lookup(err)
+
+	problem := Problem{
+		Type:   status.Type,
+		Title:  http.StatusText(status.HTTP),
+		Status: status.HTTP,
+		Detail: status.Message,
+	}
+
+	if problem.Type == "" {
+		problem.Type = "about:blank"
+	}
+
+	exposed := false
+
+	if ok && pocoErr.code != "" {
+		problem.Code = pocoErr.code
+
+		if status.Expose {
+			problem.Detail = pocoErr.message
+			exposed = true
+		}
+	}
+
+	if message, visible := UserMessage(err); visible && !exposed {
+		problem.Detail = message
+	}
+
+	return problem
+}
+
+// WriteProblem writes err as an application/problem+json response. The path
+// of req, when not nil, is used as problem instance.
+func (r *StatusRegistry) WriteProblem(w http.ResponseWriter, req *http.Request, err error) {
+	problem := r.Problem(err)
+
+	if req != nil {
+		problem.Instance = req.URL.Path
+	}
+
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(problem.Status)
+
+	_ = json.NewEncoder(w).Encode(problem)
+}
+
+func RegisterStatus(target error, status Status) {
+	DefaultStatusRegistry.Register(target, status)
+}
+
+func HTTPStatus(err error) int {
+	status, _ := DefaultStatusRegistry.Status(err)
+	return status.HTTP
+}
+
+func GRPCStatus(err error) GRPCCode {
+	status, _ := DefaultStatusRegistry.Status(err)
+	return status.GRPC
+}
+
+func WriteProblem(w http.ResponseWriter, req *http.Request, err error) {
+	DefaultStatusRegistry.WriteProblem(w, req, err)
+}
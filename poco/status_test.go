@@ -0,0 +1,172 @@
+package poco_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Arsfiqball/talker/poco"
+)
+
+func TestStatusRegistry(t *testing.T) {
+	errNotFound := poco.NewError("USER_NOT_FOUND", "user 42 not found in shard 3")
+	errInvalid := poco.NewError("INVALID_INPUT", "name is required")
+	errDB := poco.NewError("DB_ERROR", "connection refused on 10.0.0.3")
+
+	registry := poco.NewStatusRegistry()
+	registry.Register(errNotFound, poco.Status{HTTP: http.StatusNotFound, GRPC: poco.GRPCNotFound, Message: "user not found"})
+	registry.RegisterCode("INVALID_INPUT", poco.Status{HTTP: http.StatusBadRequest, GRPC: poco.GRPCInvalidArgument, Expose: true})
+	registry.Register(context.DeadlineExceeded, poco.Status{HTTP: http.StatusGatewayTimeout, GRPC: poco.GRPCDeadlineExceeded})
+
+	t.Run("match by code from outermost error", func(t *testing.T) {
+		err := errInvalid.Wrap(errNotFound.Wrap(errors.New("sql: no rows")))
+
+		status, ok := registry.Status(err)
+
+		if !ok || status.HTTP != http.StatusBadRequest || status.GRPC != poco.GRPCInvalidArgument {
+			t.Errorf("unexpected status %+v", status)
+		}
+	})
+
+	t.Run("match by errors.Is target", func(t *testing.T) {
+		err := errDB.Wrap(context.DeadlineExceeded)
+
+		status, ok := registry.Status(err)
+
+		if !ok || status.HTTP != http.StatusGatewayTimeout {
+			t.Errorf("unexpected status %+v", status)
+		}
+	})
+
+	t.Run("fallback", func(t *testing.T) {
+		status, ok := registry.Status(errDB)
+
+		if ok || status.HTTP != http.StatusInternalServerError || status.GRPC != poco.GRPCInternal {
+			t.Errorf("unexpected status %+v", status)
+		}
+	})
+
+	t.Run("problem hides internal details", func(t *testing.T) {
+		cases := []struct {
+			err    error
+			detail string
+			code   string
+		}{
+			{errNotFound, "user not found", "USER_NOT_FOUND"},
+			{errInvalid, "name is required", "INVALID_INPUT"},
+			{errDB, "", ""},
+			{errDB.Wrap(poco.NewError("QUOTA_EXCEEDED", "quota exceeded").SetUserVisible(true)), "quota exceeded", ""},
+			{errNotFound.SetUserVisible(false), "user not found", "USER_NOT_FOUND"},
+		}
+
+		for _, c := range cases {
+			problem := registry.Problem(c.err)
+
+			if problem.Detail != c.detail || problem.Code != c.code {
+				t.Errorf("unexpected problem %+v for %v", problem, c.err)
+			}
+		}
+	})
+
+	t.Run("problem does not leak errors wrapping a target", func(t *testing.T) {
+		errTimeout := errors.New("io timeout")
+
+		registry := poco.NewStatusRegistry()
+		registry.Register(errTimeout, poco.Status{HTTP: http.StatusGatewayTimeout, Expose: true})
+
+		problem := registry.Problem(poco.NewError("DB_INTERNAL", "password for db admin is wrong").Wrap(errTimeout))
+
+		if problem.Status != http.StatusGatewayTimeout || problem.Code != "" || problem.Detail != "" {
+			t.Errorf("unexpected problem %+v", problem)
+		}
+	})
+
+	t.Run("default http status", func(t *testing.T) {
+		errQuota := poco.NewError("QUOTA_EXCEEDED", "quota exceeded")
+
+		registry := poco.NewStatusRegistry()
+		registry.Register(errQuota, poco.Status{GRPC: poco.GRPCResourceExhausted})
+		registry.SetFallback(poco.Status{GRPC: poco.GRPCUnknown})
+
+		for _, err := range []error{errQuota, errDB} {
+			rec := httptest.NewRecorder()
+
+			registry.WriteProblem(rec, nil, err)
+
+			if rec.Code != http.StatusInternalServerError {
+				t.Errorf("status should be 500 for %v, got %d", err, rec.Code)
+			}
+		}
+	})
+
+	t.Run("default grpc code", func(t *testing.T) {
+		errConflict := poco.NewError("USER_EXISTS", "user exists")
+		errTeapot := poco.NewError("TEAPOT", "i am a teapot")
+
+		registry := poco.NewStatusRegistry()
+		registry.Register(errNotFound, poco.Status{HTTP: http.StatusNotFound})
+		registry.RegisterCode("USER_EXISTS", poco.Status{HTTP: http.StatusConflict})
+		registry.Register(errTeapot, poco.Status{HTTP: http.StatusTeapot})
+		registry.SetFallback(poco.Status{})
+
+		cases := []struct {
+			err  error
+			grpc poco.GRPCCode
+		}{
+			{errNotFound, poco.GRPCNotFound},
+			{errConflict, poco.GRPCAlreadyExists},
+			{errTeapot, poco.GRPCUnknown},
+			{errDB, poco.GRPCInternal},
+		}
+
+		for _, c := range cases {
+			if status, _ := registry.Status(c.err); status.GRPC != c.grpc {
+				t.Errorf("grpc code of %v should be %d, got %d", c.err, c.grpc, status.GRPC)
+			}
+		}
+	})
+
+	t.Run("write problem", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/users/42", nil)
+
+		registry.WriteProblem(rec, req, errNotFound.Wrap(errors.New("sql: no rows")))
+
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("status should be 404, got %d", rec.Code)
+		}
+
+		if rec.Header().Get("Content-Type") != "application/problem+json" {
+			t.Errorf("unexpected content type %s", rec.Header().Get("Content-Type"))
+		}
+
+		body := rec.Body.String()
+
+		if strings.Contains(body, ".go:") || strings.Contains(body, "sql") || strings.Contains(body, "shard") {
+			t.Errorf("body should not leak internal details: %s", body)
+		}
+
+		var problem poco.Problem
+
+		if err := json.Unmarshal(rec.Body.Bytes(), &problem); err != nil {
+			t.Fatal(err)
+		}
+
+		expected := poco.Problem{
+			Type:     "about:blank",
+			Title:    "Not Found",
+			Status:   http.StatusNotFound,
+			Detail:   "user not found",
+			Instance: "/users/42",
+			Code:     "USER_NOT_FOUND",
+		}
+
+		if problem != expected {
+			t.Errorf("problem should be %+v, got %+v", expected, problem)
+		}
+	})
+}
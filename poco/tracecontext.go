@@ -0,0 +1,272 @@
+package poco
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// SpanContext identifies a span across services, as carried by the W3C Trace
+// Context traceparent and tracestate headers.
+type SpanContext struct {
+	TraceID    string // TraceID is 32 lowercase hex characters.
+	SpanID     string // SpanID is 16 lowercase hex characters.
+	Sampled    bool
+	TraceState string
+	Remote     bool // Remote is set for a span context received from another service.
+}
+
+var ErrMalformedTraceParent = NewError("POCO_MALFORMED_TRACEPARENT", "malformed traceparent")
+
+// IsValid reports whether the trace and span IDs are well-formed and not zero.
+func (sc SpanContext) IsValid() bool {
+	return isHexID(sc.TraceID, 32) && isHexID(sc.SpanID, 16)
+}
+
+// TraceParent formats the span context as a traceparent header value.
+func (sc SpanContext) TraceParent() string {
+	flags := "00"
+
+	if sc.Sampled {
+		flags = "01"
+	}
+
+	return fmt.Sprintf("00-%s-%s-%s", sc.TraceID, sc.SpanID, flags)
+}
+
+// ParseTraceParent parses a traceparent header value. Values of a future
+// version are accepted as long as they start with the fields of version 00.
+func ParseTraceParent(value string) (SpanContext, error) {
+	value = strings.TrimSpace(value)
+	parts := strings.Split(value, "-")
+
+	if len(parts) < 4 || len(parts[0]) != 2 || len(parts[3]) != 2 {
+		return SpanContext{}, ErrMalformedTraceParent.Wrap()
+	}
+
+	version, err := hex.DecodeString(parts[0])
+	if err != nil || version[0] == 0xff || (version[0] == 0 && len(parts) != 4) {
+		return SpanContext{}, ErrMalformedTraceParent.Wrap()
+	}
+
+	flags, err := hex.DecodeString(parts[3])
+	if err != nil {
+		return SpanContext{}, ErrMalformedTraceParent.Wrap()
+	}
+
+	sc := SpanContext{
+		TraceID: parts[1],
+		SpanID:  parts[2],
+		Sampled: flags[0]&1 == 1,
+	}
+
+	if !sc.IsValid() {
+		return SpanContext{}, ErrMalformedTraceParent.Wrap()
+	}
+
+	return sc, nil
+}
+
+func isHexID(id string, size int) bool {
+	if len(id) != size || strings.Trim(id, "0") == "" {
+		return false
+	}
+
+	for _, c := range id {
+		if !('0' <= c && c <= '9' || 'a' <= c && c <= 'f') {
+			return false
+		}
+	}
+
+	return true
+}
+
+type spanContextKey struct{}
+
+// WithSpanContext returns a copy of ctx carrying sc.
+func WithSpanContext(ctx context.Context, sc SpanContext) context.Context {
+	return context.WithValue(ctx, spanContextKey{}, sc)
+}
+
+// SpanContextFrom returns the span context carried by ctx.
+func SpanContextFrom(ctx context.Context) (SpanContext, bool) {
+	sc, ok := ctx.Value(spanContextKey{}).(SpanContext)
+	return sc, ok
+}
+
+type parentSpanContextKey struct{}
+
+// ParentSpanContextFrom returns the span context of the parent of the span
+// started in ctx, as seen by span listeners. It is not ok for a root span.
+func ParentSpanContextFrom(ctx context.Context) (SpanContext, bool) {
+	sc, _ := ctx.Value(parentSpanContextKey{}).(SpanContext)
+	return sc, sc.IsValid()
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+
+	for {
+		_, _ = rand.Read(b)
+
+		for _, c := range b {
+			if c != 0 {
+				return hex.EncodeToString(b)
+			}
+		}
+	}
+}
+
+// childSpanContext returns a new span context in the trace of the span context
+// of ctx, or in a new trace.
+func childSpanContext(ctx context.Context, sampled bool) SpanContext {
+	sc := SpanContext{SpanID: randomHex(8), Sampled: sampled}
+
+	if parent, ok := SpanContextFrom(ctx); ok && parent.IsValid() {
+		sc.TraceID = parent.TraceID
+		sc.TraceState = parent.TraceState
+	} else {
+		sc.TraceID = randomHex(16)
+	}
+
+	return sc
+}
+
+type baggageKey struct{}
+
+// maxBaggageMembers is the number of baggage members a service must propagate
+// at least according to the W3C Baggage specification.
+const maxBaggageMembers = 180
+
+// WithBaggage returns a copy of ctx carrying the baggage of ctx with the
+// member key set to value.
+func WithBaggage(ctx context.Context, key string, value string) context.Context {
+	baggage := BaggageFrom(ctx)
+	baggage[key] = value
+
+	return context.WithValue(ctx, baggageKey{}, baggage)
+}
+
+// BaggageFrom returns a copy of the baggage carried by ctx.
+func BaggageFrom(ctx context.Context) map[string]string {
+	baggage := map[string]string{}
+
+	for key, value := range baggageOf(ctx) {
+		baggage[key] = value
+	}
+
+	return baggage
+}
+
+func baggageOf(ctx context.Context) map[string]string {
+	baggage, _ := ctx.Value(baggageKey{}).(map[string]string)
+	return baggage
+}
+
+func formatBaggage(baggage map[string]string) string {
+	keys := make([]string, 0, len(baggage))
+
+	for key := range baggage {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	members := make([]string, len(keys))
+
+	for i, key := range keys {
+		members[i] = key + "=" + url.PathEscape(baggage[key])
+	}
+
+	return strings.Join(members, ",")
+}
+
+func parseBaggage(header string) map[string]string {
+	baggage := map[string]string{}
+
+	for _, member := range strings.Split(header, ",") {
+		if len(baggage) == maxBaggageMembers {
+			break
+		}
+
+		member, _, _ = strings.Cut(member, ";")
+		key, value, ok := strings.Cut(member, "=")
+		key = strings.TrimSpace(key)
+
+		if !ok || key == "" {
+			continue
+		}
+
+		decoded, err := url.PathUnescape(strings.TrimSpace(value))
+		if err != nil {
+			continue
+		}
+
+		baggage[key] = decoded
+	}
+
+	return baggage
+}
+
+// Inject writes the span context of ctx as traceparent and tracestate headers,
+// and its baggage as baggage header.
+func Inject(ctx context.Context, header http.Header) {
+	if sc, ok := SpanContextFrom(ctx); ok && sc.IsValid() {
+		header.Set("traceparent", sc.TraceParent())
+
+		if sc.TraceState != "" {
+			header.Set("tracestate", sc.TraceState)
+		}
+	}
+
+	if baggage := baggageOf(ctx); len(baggage) > 0 {
+		header.Set("baggage", formatBaggage(baggage))
+	}
+}
+
+// Extract returns a copy of ctx carrying the remote span context of the
+// traceparent and tracestate headers and the members of the baggage headers.
+// A malformed traceparent is ignored along with tracestate.
+func Extract(ctx context.Context, header http.Header) context.Context {
+	if sc, err := ParseTraceParent(header.Get("traceparent")); err == nil {
+		sc.TraceState = strings.Join(header.Values("tracestate"), ",")
+		sc.Remote = true
+		ctx = WithSpanContext(ctx, sc)
+	}
+
+	if values := header.Values("baggage"); len(values) > 0 {
+		baggage := BaggageFrom(ctx)
+
+		for key, value := range parseBaggage(strings.Join(values, ",")) {
+			baggage[key] = value
+		}
+
+		ctx = context.WithValue(ctx, baggageKey{}, baggage)
+	}
+
+	return ctx
+}
+
+// Transport is an http.RoundTripper injecting the span context and baggage
+// of the request ctx into the request headers.
+type Transport struct {
+	Base http.RoundTripper // Base is http.DefaultTransport when nil.
+}
+
+func (t Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	req = req.Clone(req.Context())
+	Inject(req.Context(), req.Header)
+
+	return base.RoundTrip(req)
+}
@@ -0,0 +1,205 @@
+package poco_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Arsfiqball/talker/poco"
+)
+
+func TestTraceParent(t *testing.T) {
+	t.Run("should parse and format", func(t *testing.T) {
+		value := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+		sc, err := poco.ParseTraceParent(value)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if sc.TraceID != "4bf92f3577b34da6a3ce929d0e0e4736" || sc.SpanID != "00f067aa0ba902b7" || !sc.Sampled {
+			t.Errorf("unexpected span context %+v", sc)
+		}
+
+		if sc.TraceParent() != value {
+			t.Errorf("traceparent should be %s, got %s", value, sc.TraceParent())
+		}
+	})
+
+	t.Run("should accept future versions", func(t *testing.T) {
+		sc, err := poco.ParseTraceParent("cc-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00-future")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if sc.Sampled {
+			t.Error("span context should not be sampled")
+		}
+	})
+
+	t.Run("should reject malformed values", func(t *testing.T) {
+		for _, value := range []string{
+			"",
+			"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7",
+			"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+			"00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01",
+			"00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01",
+			"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra",
+			"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+			"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-zz",
+		} {
+			if _, err := poco.ParseTraceParent(value); !errors.Is(err, poco.ErrMalformedTraceParent) {
+				t.Errorf("%q should be rejected, got %v", value, err)
+			}
+		}
+	})
+
+	t.Run("should be carried by ctx", func(t *testing.T) {
+		sc := poco.SpanContext{TraceID: "4bf92f3577b34da6a3ce929d0e0e4736", SpanID: "00f067aa0ba902b7"}
+		ctx := poco.WithSpanContext(context.Background(), sc)
+
+		if found, ok := poco.SpanContextFrom(ctx); !ok || found != sc {
+			t.Errorf("span context should be found, got %+v", found)
+		}
+
+		if _, ok := poco.SpanContextFrom(context.Background()); ok {
+			t.Error("span context should not be found")
+		}
+	})
+}
+
+func TestObserverSpanContext(t *testing.T) {
+	t.Run("should generate ids for spans", func(t *testing.T) {
+		obs := poco.NewObserver()
+
+		ctx, end := obs.Span(context.Background(), "parent", nil)
+		defer end()
+
+		parent, ok := poco.SpanContextFrom(ctx)
+
+		if !ok || !parent.IsValid() || !parent.Sampled || parent.Remote {
+			t.Fatalf("unexpected parent span context %+v", parent)
+		}
+
+		childCtx, endChild := obs.Span(ctx, "child", nil)
+		defer endChild()
+
+		child, _ := poco.SpanContextFrom(childCtx)
+
+		if child.TraceID != parent.TraceID || child.SpanID == parent.SpanID {
+			t.Errorf("child should be in the trace of parent, got %+v and %+v", parent, child)
+		}
+	})
+
+	t.Run("should continue a remote trace", func(t *testing.T) {
+		remote := poco.SpanContext{
+			TraceID:    "4bf92f3577b34da6a3ce929d0e0e4736",
+			SpanID:     "00f067aa0ba902b7",
+			TraceState: "vendor=value",
+			Remote:     true,
+		}
+
+		obs := poco.NewObserver(poco.WithSampler(poco.ParentBasedSampler(poco.AlwaysSample())))
+
+		ctx, span := obs.StartSpan(poco.WithSpanContext(context.Background(), remote), "server", nil)
+
+		sc, _ := poco.SpanContextFrom(ctx)
+
+		if sc.TraceID != remote.TraceID || sc.TraceState != remote.TraceState || sc.SpanID == remote.SpanID {
+			t.Errorf("span should continue the remote trace, got %+v", sc)
+		}
+
+		if span != nil || sc.Sampled {
+			t.Error("unsampled remote parent should not be sampled")
+		}
+	})
+}
+
+func TestPropagation(t *testing.T) {
+	t.Run("should inject and extract", func(t *testing.T) {
+		ctx, end := poco.NewObserver().Span(context.Background(), "client", nil)
+		defer end()
+
+		ctx = poco.WithBaggage(ctx, "user.id", "42")
+		ctx = poco.WithBaggage(ctx, "note", "a, b=c")
+
+		header := http.Header{}
+		poco.Inject(ctx, header)
+
+		if header.Get("baggage") != "note=a%2C%20b=c,user.id=42" {
+			t.Errorf("unexpected baggage header %q", header.Get("baggage"))
+		}
+
+		extracted := poco.Extract(context.Background(), header)
+
+		sc, _ := poco.SpanContextFrom(ctx)
+		remote, ok := poco.SpanContextFrom(extracted)
+
+		if !ok || !remote.Remote || remote.TraceID != sc.TraceID || remote.SpanID != sc.SpanID {
+			t.Errorf("span context should be extracted, got %+v", remote)
+		}
+
+		baggage := poco.BaggageFrom(extracted)
+
+		if baggage["user.id"] != "42" || baggage["note"] != "a, b=c" {
+			t.Errorf("baggage should be extracted, got %v", baggage)
+		}
+	})
+
+	t.Run("should ignore malformed headers", func(t *testing.T) {
+		header := http.Header{}
+		header.Set("traceparent", "garbage")
+		header.Set("tracestate", "vendor=value")
+		header.Set("baggage", "key=value;property, =empty, novalue, bad=%zz")
+
+		ctx := poco.Extract(context.Background(), header)
+
+		if _, ok := poco.SpanContextFrom(ctx); ok {
+			t.Error("malformed traceparent should be ignored")
+		}
+
+		baggage := poco.BaggageFrom(ctx)
+
+		if len(baggage) != 1 || baggage["key"] != "value" {
+			t.Errorf("only valid members should be extracted, got %v", baggage)
+		}
+	})
+
+	t.Run("should inject headers of client requests", func(t *testing.T) {
+		var received http.Header
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			received = r.Header.Clone()
+		}))
+		defer server.Close()
+
+		ctx, end := poco.NewObserver().Span(context.Background(), "client", nil)
+		defer end()
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		client := &http.Client{Transport: poco.Transport{}}
+
+		res, err := client.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		res.Body.Close()
+
+		sc, _ := poco.SpanContextFrom(ctx)
+
+		if received.Get("traceparent") != sc.TraceParent() {
+			t.Errorf("traceparent should be injected, got %q", received.Get("traceparent"))
+		}
+
+		if req.Header.Get("traceparent") != "" {
+			t.Error("original request should not be modified")
+		}
+	})
+}
@@ -0,0 +1,101 @@
+package poco
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// ErrorDef declares an error whose data is of type T. Errors created from the
+// definition are plain poco.Error values matching the definition, and each
+// other, by code.
+type ErrorDef[T any] struct {
+	err Error
+}
+
+// DefineError declares a typed error, the generic counterpart of NewError.
+func DefineError[T any](code string, defaultMessage string) ErrorDef[T] {
+	var caller string
+
+	_, file, line, ok := runtime.Caller(1)
+
+	if ok {
+		caller = fmt.Sprintf("%s:%d", file, line)
+	}
+
+	return ErrorDef[T]{err: Error{code: code, message: defaultMessage, declaredAt: caller}}
+}
+
+func (d ErrorDef[T]) sentinel() Error {
+	return d.err
+}
+
+// Error returns the default message, so a definition can be used as
+// errors.Is target.
+func (d ErrorDef[T]) Error() string {
+	return d.err.message
+}
+
+func (d ErrorDef[T]) Code() string {
+	return d.err.code
+}
+
+// Sentinel returns the definition as untyped poco.Error.
+func (d ErrorDef[T]) Sentinel() Error {
+	return d.err
+}
+
+// New returns an error of the definition carrying data, recording where it
+// was created.
+func (d ErrorDef[T]) New(data T) Error {
+	e := d.err.wrap(1, nil)
+	e.data = data
+
+	return e
+}
+
+// Wrap returns an error of the definition carrying data and wrapping errs.
+func (d ErrorDef[T]) Wrap(data T, errs ...error) Error {
+	e := d.err.wrap(1, errs)
+	e.data = data
+
+	return e
+}
+
+// Data returns the data of the outermost error of the definition in the chain
+// of err.
+func (d ErrorDef[T]) Data(err error) (T, bool) {
+	var (
+		data  T
+		found bool
+	)
+
+	walkError(err, func(e error) bool {
+		if pocoErr, ok := e.(Error); ok && pocoErr.code == d.err.code {
+			data, found = pocoErr.data.(T)
+			return !found
+		}
+
+		return true
+	})
+
+	return data, found
+}
+
+// As returns the data of the outermost poco.Error in the chain of err whose
+// data is of type T.
+func As[T any](err error) (T, bool) {
+	var (
+		data  T
+		found bool
+	)
+
+	walkError(err, func(e error) bool {
+		if pocoErr, ok := e.(Error); ok {
+			data, found = pocoErr.data.(T)
+		}
+
+		return !found
+	})
+
+	return data, found
+}
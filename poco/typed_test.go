@@ -0,0 +1,100 @@
+package poco_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/Arsfiqball/talker/poco"
+)
+
+type notFoundData struct {
+	ID string
+}
+
+var errTypedNotFound = poco.DefineError[notFoundData]("TYPED_NOT_FOUND", "not found")
+
+func TestErrorDef(t *testing.T) {
+	t.Run("should create errors matching the definition by code", func(t *testing.T) {
+		err := errTypedNotFound.New(notFoundData{ID: "42"})
+
+		if !errors.Is(err, errTypedNotFound) {
+			t.Error("error should match the definition")
+		}
+
+		if !errors.Is(err, errTypedNotFound.Sentinel()) {
+			t.Error("error should match the sentinel")
+		}
+
+		if !errors.Is(err, poco.NewError("TYPED_NOT_FOUND", "other message")) {
+			t.Error("error should match any error with the same code")
+		}
+
+		if err.Code() != "TYPED_NOT_FOUND" || err.Error() != "not found" {
+			t.Errorf("unexpected error %s: %s", err.Code(), err)
+		}
+
+		if !strings.Contains(poco.TraceError(err)[0], "typed_test.go") {
+			t.Errorf("creation location should be recorded, got %v", poco.TraceError(err))
+		}
+	})
+
+	t.Run("should extract data from the chain", func(t *testing.T) {
+		cause := errors.New("no rows")
+		err := fmt.Errorf("handler: %w", errTypedNotFound.Wrap(notFoundData{ID: "42"}, cause))
+
+		data, ok := errTypedNotFound.Data(err)
+
+		if !ok || data.ID != "42" {
+			t.Errorf("data should be extracted, got %+v, %v", data, ok)
+		}
+
+		if !errors.Is(err, cause) {
+			t.Error("cause should be wrapped")
+		}
+
+		if _, ok := errTypedNotFound.Data(cause); ok {
+			t.Error("data should not be found without the definition in the chain")
+		}
+	})
+
+	t.Run("should be registered by code", func(t *testing.T) {
+		registry := poco.NewStatusRegistry()
+		registry.Register(errTypedNotFound, poco.Status{HTTP: http.StatusNotFound})
+
+		status, ok := registry.Status(errTypedNotFound.New(notFoundData{}))
+
+		if !ok || status.HTTP != http.StatusNotFound {
+			t.Errorf("status should be registered, got %+v", status)
+		}
+	})
+}
+
+func TestAs(t *testing.T) {
+	t.Run("should find typed data anywhere in the chain", func(t *testing.T) {
+		inner := errTypedNotFound.New(notFoundData{ID: "7"})
+		outer := poco.NewError("OUTER", "outer").SetData("text").Wrap(errors.New("other"), inner)
+
+		data, ok := poco.As[notFoundData](outer)
+
+		if !ok || data.ID != "7" {
+			t.Errorf("data should be found, got %+v, %v", data, ok)
+		}
+
+		text, ok := poco.As[string](outer)
+
+		if !ok || text != "text" {
+			t.Errorf("outermost data should be found first, got %q, %v", text, ok)
+		}
+
+		if _, ok := poco.As[int](outer); ok {
+			t.Error("data of another type should not be found")
+		}
+
+		if _, ok := poco.As[string](nil); ok {
+			t.Error("nil error should not carry data")
+		}
+	})
+}